@@ -0,0 +1,718 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/app"
+	"github.com/metagunner/habheat/pkg/config"
+	"github.com/metagunner/habheat/pkg/database"
+	"github.com/metagunner/habheat/pkg/heatmap"
+	"github.com/metagunner/habheat/pkg/journal"
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/report"
+	"github.com/metagunner/habheat/pkg/server"
+	"github.com/metagunner/habheat/pkg/todotxt"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/pressly/goose/v3"
+	"github.com/samber/lo"
+)
+
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(args []string) error
+}
+
+func getCommands() []command {
+	return []command{
+		{name: "card", usage: "card [--dark] -o card.svg", description: "Draw an SVG card with the heat map and the streaks", run: runCardCommand},
+		{name: "config", usage: "config check", description: "Validate the config file", run: runConfigCommand},
+		{name: "db", usage: "db status|up|down|version", description: "Show or migrate the database schema", run: runDbCommand},
+		{name: "journal", usage: "journal sync [--create]", description: "Sync the habits with the checklists of the daily notes, the last 7 days by default", run: runJournalCommand},
+		{name: "render", usage: "render [--year 2024] -o out.svg", description: "Draw the heat map as an SVG or a PNG image", run: runRenderCommand},
+		{name: "report", usage: "report --from <day> --to <day>", description: "Write an HTML report of the habits, e.g. report --from 2024-01-01 --to 2024-03-31 -o report.html", run: runReportCommand},
+		{name: "serve", usage: "serve [--addr :8080]", description: "Serve the web UI and the JSON API, the token is read from HABHEAT_TOKEN", run: runServeCommand},
+		{name: "todotxt", usage: "todotxt import|export", description: "Import the habits from a todo.txt file or export them to one, e.g. todotxt export -o todo.txt", run: runTodotxtCommand},
+		{name: "trash", usage: "trash empty", description: "Delete the habits in the trash forever", run: runTrashCommand},
+		{name: "vacation", usage: "vacation add|list|rm", description: "Manage the vacations, e.g. vacation add 2024-07-01 2024-07-14", run: runVacationCommand},
+	}
+}
+
+// runs the command given in the arguments, e.g. "habheat config check"
+func runCommand(args []string) error {
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage()
+		return nil
+	}
+
+	for _, cmd := range getCommands() {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
+		}
+	}
+
+	printUsage()
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  habheat                           Start the terminal UI")
+	for _, cmd := range getCommands() {
+		fmt.Fprintf(os.Stderr, "  habheat %-25s %s\n", cmd.usage, cmd.description)
+	}
+}
+
+func runConfigCommand(args []string) error {
+	if len(args) != 1 || args[0] != "check" {
+		return errors.New("usage: habheat config check")
+	}
+
+	configFilePath, err := getConfigFilePath()
+	if err != nil {
+		return err
+	}
+
+	userConfig, err := config.LoadUserConfig(configFilePath, config.GetDefaultConfig())
+	if err != nil {
+		return err
+	}
+
+	if err := config.Validate(userConfig); err != nil {
+		return fmt.Errorf("%s: %w", configFilePath, err)
+	}
+
+	fmt.Printf("%s: the config is valid\n", configFilePath)
+	return nil
+}
+
+func runDbCommand(args []string) error {
+	usage := errors.New("usage: habheat db status|up|down|version")
+	if len(args) != 1 {
+		return usage
+	}
+
+	dbPath, err := getDatabasePath()
+	if err != nil {
+		return err
+	}
+	db := database.NewDB(dbPath)
+	db.SkipMigrations = true
+	if err := db.Open(); err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	switch args[0] {
+	case "status":
+		statuses, err := db.Status(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-20s %s\n", "Applied At", "Migration")
+		for _, status := range statuses {
+			appliedAt := "Pending"
+			if status.State == goose.StateApplied {
+				appliedAt = status.AppliedAt.Local().Format(time.DateTime)
+			}
+			fmt.Printf("%-20s %s\n", appliedAt, filepath.Base(status.Source.Path))
+		}
+	case "up":
+		if _, err := db.Up(ctx); err != nil {
+			return err
+		}
+	case "down":
+		if _, err := db.Down(ctx); err != nil {
+			return err
+		}
+	case "version":
+	default:
+		return usage
+	}
+
+	current, latest, err := db.Version(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: version %d, latest version %d\n", dbPath, current, latest)
+	return nil
+}
+
+func runServeCommand(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	token := flags.String("token", os.Getenv("HABHEAT_TOKEN"), "token the clients send as a bearer token, defaults to HABHEAT_TOKEN")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.New("usage: habheat serve [--addr :8080] [--token <token>]")
+	}
+	if *token == "" {
+		return errors.New("a token is required, set HABHEAT_TOKEN or use --token")
+	}
+
+	userConfig, err := loadUserConfig()
+	if err != nil {
+		return err
+	}
+
+	dbPath, err := getDatabasePath()
+	if err != nil {
+		return err
+	}
+	db := database.NewDB(dbPath)
+	if err := db.Open(); err != nil {
+		return err
+	}
+	defer db.Close()
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           server.NewServer(database.NewHabitService(db), userConfig, *token),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("listening on %s\n", *addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func runRenderCommand(args []string) error {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	format := flags.String("format", "", "svg or png, defaults to the extension of the output file or svg")
+	year := flags.Int("year", 0, "year of the heat map, defaults to the last year")
+	theme := flags.String("theme", "", "color scheme of the config, defaults to the selected one")
+	output := flags.String("o", "-", "output file, - for the standard output")
+	noLegend := flags.Bool("no-legend", false, "don't draw the legend")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.New("usage: habheat render [--format svg|png] [--year 2024] [--theme green] [--no-legend] [-o out.svg]")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(*output), ".")
+		if *format != "png" {
+			*format = "svg"
+		}
+	}
+	if *format != "svg" && *format != "png" {
+		return fmt.Errorf("unknown format %q, use svg or png", *format)
+	}
+
+	userConfig, err := loadUserConfig()
+	if err != nil {
+		return err
+	}
+	opts, err := getHeatmapOptions(userConfig, *theme)
+	if err != nil {
+		return err
+	}
+	opts.Legend = !*noLegend
+	grid, err := loadHeatmapGrid(userConfig, *year)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*output, func(w io.Writer) error {
+		if *format == "png" {
+			// the png is not transparent so it can be pasted anywhere
+			opts.Background = "#ffffff"
+			return heatmap.WritePNG(w, grid, opts)
+		}
+		return heatmap.WriteSVG(w, grid, opts)
+	})
+}
+
+func runCardCommand(args []string) error {
+	flags := flag.NewFlagSet("card", flag.ContinueOnError)
+	year := flags.Int("year", 0, "year of the heat map, defaults to the last year")
+	theme := flags.String("theme", "", "color scheme of the config, defaults to the selected one")
+	variant := flags.String("variant", heatmap.CardLight, "light or dark")
+	dark := flags.Bool("dark", false, "same as --variant dark")
+	layout := flags.String("layout", heatmap.CardLayoutStacked, "stacked for the stats below the heat map, side for the stats on the right")
+	cellSize := flags.Int("cell-size", 11, "size of a day in pixels")
+	title := flags.String("title", "", "title of the card, defaults to the year")
+	noLegend := flags.Bool("no-legend", false, "don't draw the legend")
+	output := flags.String("o", "-", "output file, - for the standard output")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.New("usage: habheat card [--year 2024] [--theme green] [--variant light|dark] [--layout stacked|side] [--cell-size 11] [--title title] [--no-legend] [-o card.svg]")
+	}
+	if *dark {
+		*variant = heatmap.CardDark
+	}
+	if !heatmap.IsCardVariant(*variant) {
+		return fmt.Errorf("unknown variant %q, use light or dark", *variant)
+	}
+	if !heatmap.IsCardLayout(*layout) {
+		return fmt.Errorf("unknown layout %q, use stacked or side", *layout)
+	}
+	if *cellSize < 4 || *cellSize > 40 {
+		return fmt.Errorf("invalid cell size %d, use a size between 4 and 40", *cellSize)
+	}
+	if *title == "" {
+		*title = lo.Ternary(*year == 0, "Habits of the last year", fmt.Sprintf("Habits of %d", *year))
+	}
+
+	userConfig, err := loadUserConfig()
+	if err != nil {
+		return err
+	}
+	opts, err := getHeatmapOptions(userConfig, *theme)
+	if err != nil {
+		return err
+	}
+	opts.CellSize = *cellSize
+	opts.Gap = max(1, *cellSize/4)
+	opts.Legend = !*noLegend
+	grid, err := loadHeatmapGrid(userConfig, *year)
+	if err != nil {
+		return err
+	}
+
+	cardOpts := heatmap.CardOptions{Options: opts, Title: *title, Variant: *variant, Layout: *layout}
+	return writeOutput(*output, func(w io.Writer) error {
+		return heatmap.WriteCard(w, grid, cardOpts)
+	})
+}
+
+func runReportCommand(args []string) error {
+	usage := errors.New("usage: habheat report --from 2024-01-01 --to 2024-03-31 [--theme green] [-o report.html]")
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	fromFlag := flags.String("from", "", "first day of the report")
+	toFlag := flags.String("to", "", "last day of the report")
+	theme := flags.String("theme", "", "color scheme of the config, defaults to the selected one")
+	output := flags.String("o", "-", "output file, - for the standard output")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 || *fromFlag == "" || *toFlag == "" {
+		return usage
+	}
+	from, err := time.Parse(time.DateOnly, *fromFlag)
+	if err != nil {
+		return fmt.Errorf("invalid day %q, use the YYYY-MM-DD format", *fromFlag)
+	}
+	to, err := time.Parse(time.DateOnly, *toFlag)
+	if err != nil {
+		return fmt.Errorf("invalid day %q, use the YYYY-MM-DD format", *toFlag)
+	}
+	if from.After(to) {
+		return fmt.Errorf("the from day %s is after the to day %s", *fromFlag, *toFlag)
+	}
+
+	userConfig, err := loadUserConfig()
+	if err != nil {
+		return err
+	}
+	opts, err := getHeatmapOptions(userConfig, *theme)
+	if err != nil {
+		return err
+	}
+
+	dbPath, err := getDatabasePath()
+	if err != nil {
+		return err
+	}
+	db := database.NewDB(dbPath)
+	if err := db.Open(); err != nil {
+		return err
+	}
+	defer db.Close()
+
+	habitReport, err := report.New(context.Background(), database.NewHabitService(db), from, to, userConfig.Gui.GetWeekStart(), opts, utils.Today())
+	if err != nil {
+		return err
+	}
+	return writeOutput(*output, habitReport.WriteHTML)
+}
+
+// parses the from and to flags of the YYYY-MM-DD days, the to day defaults to
+// today and the from day to 6 days before the to day
+func getDayRange(fromFlag string, toFlag string) (time.Time, time.Time, error) {
+	var err error
+	to := utils.Today()
+	if toFlag != "" {
+		if to, err = time.Parse(time.DateOnly, toFlag); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid day %q, use the YYYY-MM-DD format", toFlag)
+		}
+	}
+	from := to.AddDate(0, 0, -6)
+	if fromFlag != "" {
+		if from, err = time.Parse(time.DateOnly, fromFlag); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid day %q, use the YYYY-MM-DD format", fromFlag)
+		}
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("the from day %s is after the to day %s", from.Format(time.DateOnly), to.Format(time.DateOnly))
+	}
+	return from, to, nil
+}
+
+// returns the options to draw the heat map with the color scheme of the config,
+// the selected one when the name is empty
+func getHeatmapOptions(userConfig *config.UserConfig, schemeName string) (heatmap.Options, error) {
+	if schemeName == "" {
+		schemeName = userConfig.Gui.Theme.Selected
+	}
+	scheme, ok := userConfig.Gui.Theme.ColorSchemes[schemeName]
+	if !ok {
+		names := lo.Keys(userConfig.Gui.Theme.ColorSchemes)
+		sort.Strings(names)
+		return heatmap.Options{}, fmt.Errorf("unknown color scheme %q, available schemes are %s", schemeName, strings.Join(names, ", "))
+	}
+	locale, _ := utils.GetLocale(userConfig.Gui.Language)
+	return heatmap.DefaultOptions(scheme, locale), nil
+}
+
+// returns the heat map of the year, the last year when the year is zero
+func loadHeatmapGrid(userConfig *config.UserConfig, year int) (*heatmap.Grid, error) {
+	dbPath, err := getDatabasePath()
+	if err != nil {
+		return nil, err
+	}
+	db := database.NewDB(dbPath)
+	if err := db.Open(); err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	weekStart := userConfig.Gui.GetWeekStart()
+	from, to := heatmap.LastYearRange(utils.Today(), weekStart)
+	if year != 0 {
+		from, to = heatmap.YearRange(year)
+	}
+	heatmaps, _, err := database.NewHabitService(db).HeatMap(context.Background(), from, to)
+	if err != nil {
+		return nil, err
+	}
+	return heatmap.NewGrid(from, to, weekStart, heatmaps, utils.Today()), nil
+}
+
+// writes to the file, or to the standard output when the path is "-"
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote %s\n", path)
+	return nil
+}
+
+func runJournalCommand(args []string) error {
+	usage := errors.New("usage: habheat journal sync [--from 2024-07-01] [--to 2024-07-07] [--dir notes] [--create]")
+	if len(args) == 0 || args[0] != "sync" {
+		return usage
+	}
+	flags := flag.NewFlagSet("journal sync", flag.ContinueOnError)
+	fromFlag := flags.String("from", "", "first day to sync, defaults to 6 days before the to day")
+	toFlag := flags.String("to", "", "last day to sync, defaults to today")
+	dir := flags.String("dir", "", "directory of the daily notes, defaults to journal.directory of the config")
+	create := flags.Bool("create", false, "write the notes of the days with habits that don't have a note")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return usage
+	}
+
+	userConfig, err := loadUserConfig()
+	if err != nil {
+		return err
+	}
+	from, to, err := getDayRange(*fromFlag, *toFlag)
+	if err != nil {
+		return err
+	}
+
+	if *dir == "" {
+		if *dir, err = userConfig.Journal.GetDirectory(); err != nil {
+			return err
+		}
+	}
+	if *dir == "" {
+		return errors.New("set journal.directory in the config or use --dir")
+	}
+	if info, err := os.Stat(*dir); err != nil {
+		return err
+	} else if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", *dir)
+	}
+
+	dbPath, err := getDatabasePath()
+	if err != nil {
+		return err
+	}
+	db := database.NewDB(dbPath)
+	if err := db.Open(); err != nil {
+		return err
+	}
+	defer db.Close()
+
+	days := []time.Time{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	syncer := journal.NewSyncer(database.NewHabitService(db), *dir, userConfig.Journal.Heading)
+	syncer.CreateNotes = *create
+	result, err := syncer.Sync(context.Background(), days)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("created %d habits, updated %d habits, deleted %d habits, wrote %d notes\n", result.CreatedHabits, result.UpdatedHabits, result.DeletedHabits, result.WrittenNotes)
+	describe := func(item *journal.Item) string {
+		if item == nil {
+			return "deleted it"
+		}
+		return fmt.Sprintf("has %q", item.String())
+	}
+	for _, conflict := range result.Conflicts {
+		fmt.Fprintf(os.Stderr, "conflict on %s: the note %s, habheat %s\n",
+			conflict.Day.Format(time.DateOnly), describe(conflict.Note), describe(conflict.Habit))
+	}
+	for _, skipped := range result.Skipped {
+		fmt.Fprintf(os.Stderr, "skipped on %s: %q, %s\n", skipped.Day.Format(time.DateOnly), skipped.Title, app.ErrorMessage(skipped.Err))
+	}
+	if len(result.Conflicts) > 0 {
+		return fmt.Errorf("%d habits are changed both in the notes and in habheat, change one of them back and sync again", len(result.Conflicts))
+	}
+	if len(result.Skipped) > 0 {
+		return fmt.Errorf("%d checkboxes can't be habits, change them and sync again", len(result.Skipped))
+	}
+	return nil
+}
+
+func runTodotxtCommand(args []string) error {
+	usage := errors.New("usage: habheat todotxt export [--from 2024-07-01] [--to 2024-07-07] [-o todo.txt] | todotxt import [todo.txt]")
+	if len(args) == 0 || (args[0] != "import" && args[0] != "export") {
+		return usage
+	}
+	flags := flag.NewFlagSet("todotxt "+args[0], flag.ContinueOnError)
+	project := flags.String("project", "habit", "project of the habits, empty to import every task")
+	fromFlag := flags.String("from", "", "first day to export, defaults to 6 days before the to day")
+	toFlag := flags.String("to", "", "last day to export, defaults to today")
+	output := flags.String("o", "-", "output file, - for the standard output")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if (args[0] == "export" && flags.NArg() > 0) || flags.NArg() > 1 {
+		return usage
+	}
+
+	if _, err := loadUserConfig(); err != nil {
+		return err
+	}
+	dbPath, err := getDatabasePath()
+	if err != nil {
+		return err
+	}
+	db := database.NewDB(dbPath)
+	if err := db.Open(); err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	habitService := database.NewHabitService(db)
+	if args[0] == "export" {
+		from, to, err := getDayRange(*fromFlag, *toFlag)
+		if err != nil {
+			return err
+		}
+		tasks, err := todotxt.Export(ctx, habitService, from, to, *project)
+		if err != nil {
+			return err
+		}
+		return writeOutput(*output, func(w io.Writer) error {
+			return todotxt.Write(w, tasks)
+		})
+	}
+
+	// the tasks are read from the standard input without a file
+	var input io.Reader = os.Stdin
+	if path := flags.Arg(0); path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		input = f
+	}
+	tasks, err := todotxt.Read(input)
+	if err != nil {
+		return err
+	}
+	result, err := todotxt.Import(ctx, habitService, tasks, *project, utils.Today())
+	var taskErr *todotxt.TaskError
+	if errors.As(err, &taskErr) && app.ErrorCode(err) == app.EINVALID {
+		return fmt.Errorf("%q: %s", taskErr.Task.String(), app.ErrorMessage(err))
+	} else if err != nil {
+		return err
+	}
+	fmt.Printf("created %d habits, updated %d habits\n", result.Created, result.Updated)
+	if result.Ignored > 0 {
+		fmt.Printf("ignored %d tasks without +%s\n", result.Ignored, *project)
+	}
+	return nil
+}
+
+func runTrashCommand(args []string) error {
+	if len(args) != 1 || args[0] != "empty" {
+		return errors.New("usage: habheat trash empty")
+	}
+
+	dbPath, err := getDatabasePath()
+	if err != nil {
+		return err
+	}
+	db := database.NewDB(dbPath)
+	if err := db.Open(); err != nil {
+		return err
+	}
+	defer db.Close()
+
+	n, err := database.NewHabitService(db).EmptyTrash(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("deleted %d habits from the trash\n", n)
+	return nil
+}
+
+func runVacationCommand(args []string) error {
+	usage := errors.New("usage: habheat vacation add <from> <to> | vacation list | vacation rm <id>")
+	if len(args) == 0 {
+		return usage
+	}
+
+	dbPath, err := getDatabasePath()
+	if err != nil {
+		return err
+	}
+	db := database.NewDB(dbPath)
+	if err := db.Open(); err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	service := database.NewVacationService(db)
+	switch {
+	case args[0] == "add" && len(args) == 3:
+		from, err := time.Parse(time.DateOnly, args[1])
+		if err != nil {
+			return fmt.Errorf("invalid day %q, use the YYYY-MM-DD format", args[1])
+		}
+		to, err := time.Parse(time.DateOnly, args[2])
+		if err != nil {
+			return fmt.Errorf("invalid day %q, use the YYYY-MM-DD format", args[2])
+		}
+		vacation, err := models.CreateVacation(from, to)
+		if err != nil {
+			return errors.New(app.ErrorMessage(err))
+		}
+		if err := service.Create(ctx, vacation); err != nil {
+			return err
+		}
+		fmt.Printf("added vacation %d from %s to %s\n", vacation.Id, args[1], args[2])
+	case args[0] == "list" && len(args) == 1:
+		vacations, err := service.List(ctx)
+		if err != nil {
+			return err
+		}
+		for _, vacation := range vacations {
+			fmt.Printf("%-4d %s - %s\n", vacation.Id, vacation.From.Format(time.DateOnly), vacation.To.Format(time.DateOnly))
+		}
+	case args[0] == "rm" && len(args) == 2:
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return usage
+		}
+		if err := service.Delete(ctx, models.VacationId(id)); err != nil {
+			return errors.New(app.ErrorMessage(err))
+		}
+		fmt.Printf("removed vacation %d\n", id)
+	default:
+		return usage
+	}
+	return nil
+}
+
+// loads and validates the user config and sets the day clock of the config, the
+// day clock decides which day is today
+func loadUserConfig() (*config.UserConfig, error) {
+	configFilePath, err := getConfigFilePath()
+	if err != nil {
+		return nil, err
+	}
+	userConfig, err := config.LoadUserConfig(configFilePath, config.GetDefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+	if err := config.Validate(userConfig); err != nil {
+		return nil, fmt.Errorf("%s: %w", configFilePath, err)
+	}
+	utils.SetDayClock(userConfig.Gui.GetDayClock())
+	return userConfig, nil
+}
+
+// returns the config file path, the config directory is created if it does not exist
+func getConfigFilePath() (string, error) {
+	configDir, err := findOrCreateConfigDir()
+	if err != nil && !os.IsPermission(err) {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "config.yml"), nil
+}
+
+// returns the database file path, it is next to the config file
+func getDatabasePath() (string, error) {
+	configFilePath, err := getConfigFilePath()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(filepath.Dir(configFilePath), "test.db"), nil
+}
@@ -9,6 +9,8 @@ require (
 	github.com/pressly/goose/v3 v3.21.1
 	github.com/samber/lo v1.44.0
 	github.com/stretchr/testify v1.9.0
+	golang.org/x/image v0.18.0
+	golang.org/x/sys v0.22.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -25,7 +27,6 @@ require (
 	github.com/sethvargo/go-retry v0.2.4 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/sync v0.7.0 // indirect
-	golang.org/x/sys v0.22.0 // indirect
 	golang.org/x/term v0.17.0 // indirect
 	golang.org/x/text v0.16.0 // indirect
 )
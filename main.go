@@ -23,26 +23,37 @@ var version string
 func main() {
 	checkVersion()
 
-	configDir, err := findOrCreateConfigDir()
-	if err != nil && !os.IsPermission(err) {
-		panic(err)
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	configFilePath := filepath.Join(configDir, "config.yml")
-	config, err := loadUserConfig(configFilePath, config.GetDefaultConfig())
+	userConfig, err := loadUserConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\nRun `habheat config check` after fixing the config.\n", err)
+		os.Exit(1)
+	}
+	configFilePath, err := getConfigFilePath()
 	if err != nil {
 		panic(err)
 	}
 
 	// HeatmapGrid()
-	dbPath := filepath.Join(configDir, "test.db")
+	dbPath, err := getDatabasePath()
+	if err != nil {
+		panic(err)
+	}
 	db := database.NewDB(dbPath)
 	if err := db.Open(); err != nil {
-		panic(err)
+		fmt.Fprintf(os.Stderr, "%s: %s\n", dbPath, err)
+		os.Exit(1)
 	}
 	// database.SeedTestData(context.Background(), db, 2023, 7)
 
-	gui := gui.NewGui(config, db, version)
+	gui := gui.NewGui(userConfig, configFilePath, db, version)
 	err = gui.Run()
 	if err != nil {
 		if !errors.Is(err, gocui.ErrQuit) {
@@ -51,36 +62,6 @@ func main() {
 	}
 }
 
-// loads the user config with defaults
-func loadUserConfig(configFilePath string, base *config.UserConfig) (*config.UserConfig, error) {
-	if _, err := os.Stat(configFilePath); err != nil {
-		if !os.IsNotExist(err) {
-			return nil, err
-		}
-
-		// create the config file if it does not exist
-		file, err := os.Create(configFilePath)
-		if err != nil {
-			if os.IsPermission(err) {
-				panic(err)
-			}
-			return nil, err
-		}
-		file.Close()
-	}
-
-	content, err := os.ReadFile(configFilePath)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := yaml.Unmarshal(content, base); err != nil {
-		return nil, fmt.Errorf("The config at `%s` couldn't be parsed, please inspect it before opening up an issue.\n%w", configFilePath, err)
-	}
-
-	return base, nil
-}
-
 func findOrCreateConfigDir() (string, error) {
 	// look for habheat/filename in XDG_CONFIG_HOME and XDG_CONFIG_DIRS
 	configFilepath, err := xdg.SearchConfigFile(filepath.Join("habheat", "config.yml"))
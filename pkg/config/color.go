@@ -0,0 +1,269 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ColorProfile is the color support of the terminal.
+type ColorProfile int
+
+const (
+	// 16 colors, the basic ANSI colors and their bright variants
+	ColorProfileANSI ColorProfile = iota
+	// 256 colors of the xterm palette
+	ColorProfileANSI256
+	// 24 bit colors
+	ColorProfileTrueColor
+)
+
+// DetectColorProfile returns the color support of the terminal from the environment.
+func DetectColorProfile() ColorProfile {
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorTerm == "truecolor" || colorTerm == "24bit" || os.Getenv("WT_SESSION") != "" {
+		return ColorProfileTrueColor
+	}
+
+	term := strings.ToLower(os.Getenv("TERM"))
+	if strings.Contains(term, "truecolor") || strings.Contains(term, "24bit") || strings.Contains(term, "direct") {
+		return ColorProfileTrueColor
+	}
+	if strings.Contains(term, "256") {
+		return ColorProfileANSI256
+	}
+	return ColorProfileANSI
+}
+
+// Color is a color of the heat map cells. A color keeps the palette index when
+// it is defined by one so the terminal can use its own palette.
+type Color struct {
+	R, G, B uint8
+	// index of the color in the 256 color palette, -1 for hex colors
+	Index int
+}
+
+// Hex returns the color in the #rrggbb format.
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+var namedColors = map[string]int{
+	"black":          0,
+	"red":            1,
+	"green":          2,
+	"yellow":         3,
+	"blue":           4,
+	"magenta":        5,
+	"cyan":           6,
+	"white":          7,
+	"bright-black":   8,
+	"gray":           8,
+	"grey":           8,
+	"bright-red":     9,
+	"bright-green":   10,
+	"bright-yellow":  11,
+	"bright-blue":    12,
+	"bright-magenta": 13,
+	"bright-cyan":    14,
+	"bright-white":   15,
+}
+
+// rgb values of the first 16 colors as the xterm shows them
+var ansiPalette = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+var (
+	hexColorRegexp = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+	// background colors of the raw escape sequences, e.g. "\033[48;5;22m  \033[0m"
+	escape256Regexp  = regexp.MustCompile(`\x1b\[48;5;(\d{1,3})m`)
+	escapeTrueRegexp = regexp.MustCompile(`\x1b\[48;2;(\d{1,3});(\d{1,3});(\d{1,3})m`)
+	escapeANSIRegexp = regexp.MustCompile(`\x1b\[(4[0-7]|10[0-7])m`)
+)
+
+// ParseColor parses a hex color (#216e39), a 256 color palette index (22), a
+// named color (green) or a raw ANSI escape sequence with a background color.
+// Returns false for any other value like spaces or emojis.
+func ParseColor(value string) (Color, bool) {
+	value = strings.TrimSpace(value)
+	if hexColorRegexp.MatchString(value) {
+		hex := value[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		rgb, _ := strconv.ParseUint(hex, 16, 32)
+		return Color{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), Index: -1}, true
+	}
+
+	if index, err := strconv.Atoi(value); err == nil {
+		if index < 0 || index > 255 {
+			return Color{}, false
+		}
+		return colorFromIndex(index), true
+	}
+
+	if index, ok := namedColors[strings.ToLower(value)]; ok {
+		return colorFromIndex(index), true
+	}
+
+	if match := escapeTrueRegexp.FindStringSubmatch(value); match != nil {
+		r, _ := strconv.Atoi(match[1])
+		g, _ := strconv.Atoi(match[2])
+		b, _ := strconv.Atoi(match[3])
+		return Color{R: uint8(r), G: uint8(g), B: uint8(b), Index: -1}, true
+	}
+	if match := escape256Regexp.FindStringSubmatch(value); match != nil {
+		index, _ := strconv.Atoi(match[1])
+		if index <= 255 {
+			return colorFromIndex(index), true
+		}
+	}
+	if match := escapeANSIRegexp.FindStringSubmatch(value); match != nil {
+		code, _ := strconv.Atoi(match[1])
+		if code >= 100 {
+			return colorFromIndex(code - 100 + 8), true
+		}
+		return colorFromIndex(code - 40), true
+	}
+
+	return Color{}, false
+}
+
+func colorFromIndex(index int) Color {
+	switch {
+	case index < 16:
+		rgb := ansiPalette[index]
+		return Color{R: rgb[0], G: rgb[1], B: rgb[2], Index: index}
+	case index < 232:
+		i := index - 16
+		return Color{R: cubeLevels[i/36], G: cubeLevels[(i/6)%6], B: cubeLevels[i%6], Index: index}
+	default:
+		level := uint8(8 + (index-232)*10)
+		return Color{R: level, G: level, B: level, Index: index}
+	}
+}
+
+// BackgroundEscape returns the escape sequence that sets the color as the
+// background, the color is downsampled to the given profile.
+func (c Color) BackgroundEscape(profile ColorProfile) string {
+	switch profile {
+	case ColorProfileTrueColor:
+		if c.Index >= 0 {
+			return fmt.Sprintf("\033[48;5;%dm", c.Index)
+		}
+		return fmt.Sprintf("\033[48;2;%d;%d;%dm", c.R, c.G, c.B)
+	case ColorProfileANSI256:
+		if c.Index >= 0 {
+			return fmt.Sprintf("\033[48;5;%dm", c.Index)
+		}
+		return fmt.Sprintf("\033[48;5;%dm", c.nearestIndex(16, 256))
+	default:
+		index := c.Index
+		if index < 0 || index >= 16 {
+			index = c.nearestANSIIndex()
+		}
+		if index >= 8 {
+			return fmt.Sprintf("\033[%dm", 100+index-8)
+		}
+		return fmt.Sprintf("\033[%dm", 40+index)
+	}
+}
+
+// returns the palette index in [from, to) that is the closest to the color
+func (c Color) nearestIndex(from int, to int) int {
+	candidates := make([]int, 0, to-from)
+	for i := from; i < to; i++ {
+		candidates = append(candidates, i)
+	}
+	return c.nearestOf(candidates)
+}
+
+// Returns the ANSI color with the closest hue, bright variants are used for
+// light colors. Matching by distance turns the dark shades to gray or blue.
+func (c Color) nearestANSIIndex() int {
+	r, g, b := float64(c.R), float64(c.G), float64(c.B)
+	maxComponent := max(r, g, b)
+	delta := maxComponent - min(r, g, b)
+	if delta < 32 {
+		return c.nearestOf([]int{0, 7, 8, 15})
+	}
+
+	var hue float64
+	switch maxComponent {
+	case r:
+		hue = math.Mod((g-b)/delta, 6)
+	case g:
+		hue = (b-r)/delta + 2
+	default:
+		hue = (r-g)/delta + 4
+	}
+	if hue < 0 {
+		hue += 6
+	}
+
+	// red, yellow, green, cyan, blue, magenta
+	hues := []int{1, 3, 2, 6, 4, 5}
+	index := hues[int(math.Round(hue))%len(hues)]
+	if maxComponent >= 192 {
+		index += 8
+	}
+	return index
+}
+
+func (c Color) nearestOf(candidates []int) int {
+	nearest := candidates[0]
+	minDistance := math.MaxFloat64
+	for _, i := range candidates {
+		candidate := colorFromIndex(i)
+		dr := float64(c.R) - float64(candidate.R)
+		dg := float64(c.G) - float64(candidate.G)
+		db := float64(c.B) - float64(candidate.B)
+		// weighted for the sensitivity of the eye
+		distance := 0.3*dr*dr + 0.59*dg*dg + 0.11*db*db
+		if distance < minDistance {
+			minDistance = distance
+			nearest = i
+		}
+	}
+	return nearest
+}
+
+// GetCellValue returns the text printed for a heat map cell. Colors are
+// printed as two colored spaces, raw escape sequences and any other text like
+// emojis are printed as they are.
+func GetCellValue(value string, profile ColorProfile) string {
+	if strings.Contains(value, "\033") {
+		return value
+	}
+	color, ok := ParseColor(value)
+	if !ok {
+		return value
+	}
+	return color.BackgroundEscape(profile) + space + end
+}
+
+// Resolve returns the color scheme with every value converted to the text
+// printed in the heat map for the given profile.
+func (s HeatmapColorScheme) Resolve(profile ColorProfile) HeatmapColorScheme {
+	resolved := s
+	resolved.InvalidDayValue = GetCellValue(s.InvalidDayValue, profile)
+	resolved.NoHabitsValue = GetCellValue(s.NoHabitsValue, profile)
+	resolved.ZeroCompletedHabitValue = GetCellValue(s.ZeroCompletedHabitValue, profile)
+	resolved.CursorValue = GetCellValue(s.CursorValue, profile)
+	resolved.SkippedValue = GetCellValue(s.GetSkippedValue(), profile)
+	resolved.SelectedValue = GetCellValue(s.GetSelectedValue(), profile)
+	resolved.StatusValues = make(map[int]string, len(s.StatusValues))
+	for level, value := range s.StatusValues {
+		resolved.StatusValues[level] = GetCellValue(value, profile)
+	}
+	return resolved
+}
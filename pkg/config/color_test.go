@@ -0,0 +1,76 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		color Color
+		ok    bool
+	}{
+		{"Given hex color should succeed", "#216e39", Color{R: 0x21, G: 0x6e, B: 0x39, Index: -1}, true},
+		{"Given short hex color should succeed", "#fa0", Color{R: 0xff, G: 0xaa, B: 0x00, Index: -1}, true},
+		{"Given palette index should succeed", "22", Color{R: 0, G: 95, B: 0, Index: 22}, true},
+		{"Given grayscale palette index should succeed", "232", Color{R: 8, G: 8, B: 8, Index: 232}, true},
+		{"Given named color should succeed", "Green", Color{R: 0, G: 205, B: 0, Index: 2}, true},
+		{"Given raw 256 color escape should succeed", "\033[48;5;22m  \033[0m", Color{R: 0, G: 95, B: 0, Index: 22}, true},
+		{"Given raw true color escape should succeed", "\033[48;2;33;110;57m  \033[0m", Color{R: 33, G: 110, B: 57, Index: -1}, true},
+		{"Given raw bright color escape should succeed", "\033[102m  \033[0m", Color{R: 0, G: 255, B: 0, Index: 10}, true},
+		{"Given out of range palette index should fail", "256", Color{}, false},
+		{"Given invalid hex color should fail", "#21e39", Color{}, false},
+		{"Given spaces should fail", "  ", Color{}, false},
+		{"Given emoji should fail", "🤩", Color{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			color, ok := ParseColor(tt.value)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.color, color)
+		})
+	}
+}
+
+func TestGetCellValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		profile ColorProfile
+		want    string
+	}{
+		{"Given hex color on true color terminal should use rgb", "#216e39", ColorProfileTrueColor, "\033[48;2;33;110;57m  \033[0m"},
+		{"Given hex color on 256 color terminal should use the nearest palette color", "#216e39", ColorProfileANSI256, "\033[48;5;23m  \033[0m"},
+		{"Given hex color on 16 color terminal should use the nearest ansi color", "#216e39", ColorProfileANSI, "\033[42m  \033[0m"},
+		{"Given palette index on true color terminal should keep the index", "22", ColorProfileTrueColor, "\033[48;5;22m  \033[0m"},
+		{"Given palette index on 16 color terminal should use the nearest ansi color", "40", ColorProfileANSI, "\033[102m  \033[0m"},
+		{"Given named color should use the ansi color", "bright-red", ColorProfileANSI256, "\033[48;5;9m  \033[0m"},
+		{"Given raw escape should keep it", "\033[48;5;22m  \033[0m", ColorProfileANSI, "\033[48;5;22m  \033[0m"},
+		{"Given gray hex color on 16 color terminal should use a gray", "#303030", ColorProfileANSI, "\033[40m  \033[0m"},
+		{"Given emoji should keep it", "🤩", ColorProfileTrueColor, "🤩"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetCellValue(tt.value, tt.profile))
+		})
+	}
+}
+
+func TestDetectColorProfile(t *testing.T) {
+	t.Setenv("WT_SESSION", "")
+
+	t.Setenv("COLORTERM", "truecolor")
+	assert.Equal(t, ColorProfileTrueColor, DetectColorProfile())
+
+	t.Setenv("COLORTERM", "")
+	t.Setenv("TERM", "xterm-256color")
+	assert.Equal(t, ColorProfileANSI256, DetectColorProfile())
+
+	t.Setenv("TERM", "xterm")
+	assert.Equal(t, ColorProfileANSI, DetectColorProfile())
+}
@@ -3,7 +3,7 @@ package config
 // taken from https://github.com/jesseduffield/lazygit/blob/master/pkg/gui/keybindings/keybindings.go
 
 import (
-	"log"
+	"fmt"
 	"strings"
 	"unicode/utf8"
 
@@ -78,19 +78,257 @@ var labelByKey = map[gocui.Key]string{
 
 var keyByLabel = lo.Invert(labelByKey)
 
-func GetKey(key string) interface{} {
-	runeCount := utf8.RuneCountInString(key)
-	if key == "<disabled>" {
-		return nil
-	} else if runeCount > 1 {
-		binding, ok := keyByLabel[strings.ToLower(key)]
-		if !ok {
-			log.Fatalf("Unrecognized key %s for keybinding. For permitted values see %s", strings.ToLower(key), "https://github.com/metagunner/habheat?tab=readme-ov-file#keybindings")
-		} else {
-			return binding
+// Key is a single key press of a keybinding, e.g. "j" or "<a-j>".
+type Key struct {
+	// gocui.Key or rune
+	Key interface{}
+	Mod gocui.Modifier
+}
+
+// Label returns the key as it is shown to the user, e.g. "<space>" or "<a-j>".
+func (k Key) Label() string {
+	label := ""
+	switch key := k.Key.(type) {
+	case gocui.Key:
+		label = labelByKey[key]
+	case rune:
+		label = string(key)
+	}
+	if k.Mod == gocui.ModAlt {
+		return "<a-" + strings.TrimSuffix(strings.TrimPrefix(label, "<"), ">") + ">"
+	}
+	return label
+}
+
+// GetSequenceLabel returns the key presses of a sequence as they are shown to the user, e.g. "gg".
+func GetSequenceLabel(keys []Key) string {
+	var label strings.Builder
+	for _, key := range keys {
+		label.WriteString(key.Label())
+	}
+	return label.String()
+}
+
+// ParseKeys parses a keybinding from the config. The alternative keys are
+// separated by spaces, e.g. "k <up>", and a key can be a sequence of key
+// presses, e.g. "gg". The Alt modifier is written as "<a-j>". Disabled
+// keybindings have no keys.
+func ParseKeys(keys string) ([][]Key, error) {
+	if strings.TrimSpace(keys) == "<disabled>" {
+		return nil, nil
+	}
+	fields := strings.Fields(keys)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty keybinding, use <disabled> to disable it")
+	}
+
+	result := make([][]Key, 0, len(fields))
+	for _, field := range fields {
+		sequence, err := parseSequence(field)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, sequence)
+	}
+	return result, nil
+}
+
+// parses the key presses of a sequence, e.g. "g<down>"
+func parseSequence(sequence string) ([]Key, error) {
+	result := []Key{}
+	for rest := sequence; rest != ""; {
+		end := strings.Index(rest, ">")
+		if !strings.HasPrefix(rest, "<") || end < 1 {
+			// a single rune, "<" is a rune as well when no label follows it
+			r, size := utf8.DecodeRuneInString(rest)
+			result = append(result, Key{Key: r})
+			rest = rest[size:]
+			continue
+		}
+
+		key, err := parseLabel(rest[:end+1])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, key)
+		rest = rest[end+1:]
+	}
+	return result, nil
+}
+
+// parses a key label, e.g. "<c-a>", "<a-j>" or "<a-up>"
+func parseLabel(label string) (Key, error) {
+	if key, ok := keyByLabel[strings.ToLower(label)]; ok {
+		return Key{Key: key}, nil
+	}
+
+	name := label[1 : len(label)-1]
+	if strings.HasPrefix(strings.ToLower(name), "a-") {
+		name = name[2:]
+		if utf8.RuneCountInString(name) == 1 {
+			return Key{Key: []rune(name)[0], Mod: gocui.ModAlt}, nil
 		}
-	} else if runeCount == 1 {
-		return []rune(key)[0]
+		if key, ok := keyByLabel["<"+strings.ToLower(name)+">"]; ok {
+			return Key{Key: key, Mod: gocui.ModAlt}, nil
+		}
+	}
+	return Key{}, fmt.Errorf("unrecognized key %s for keybinding. For permitted values see %s", strings.ToLower(label), "https://github.com/metagunner/habheat?tab=readme-ov-file#keybindings")
+}
+
+// GetKeyLabel returns the keys as they are shown to the user, e.g. "<space>"
+// or "k <up>". Disabled and unknown keys return an empty label, unknown keys
+// are reported by Validate.
+func GetKeyLabel(keys string) string {
+	sequences, err := ParseKeys(keys)
+	if err != nil {
+		return ""
+	}
+	labels := make([]string, 0, len(sequences))
+	for _, sequence := range sequences {
+		labels = append(labels, GetSequenceLabel(sequence))
+	}
+	return strings.Join(labels, " ")
+}
+
+// Binding is a keybinding with a human readable description of its action.
+type Binding struct {
+	Key         string
+	Description string
+	// Name of the action the key runs, the gui registers a handler for every
+	// action of a view
+	Action string
+	// Navigation bindings are listed in the help but not in the status hints.
+	Navigation bool
+}
+
+// ViewBindings groups the keybindings that are active in a view.
+type ViewBindings struct {
+	// Name of the gocui view, empty for the global keybindings.
+	View     string
+	Title    string
+	Bindings []Binding
+}
+
+// GetViewBindings returns the keybindings of every view in display order.
+func (c KeybindingConfig) GetViewBindings() []ViewBindings {
+	universal := c.Universal
+	heatmap := c.Heatmap
+	listNavigation := []Binding{
+		{Key: universal.PrevItem, Description: "Scroll up", Action: "prevItem", Navigation: true},
+		{Key: universal.NextItem, Description: "Scroll down", Action: "nextItem", Navigation: true},
+		{Key: universal.PrevItemAlt, Description: "Scroll up alternative", Action: "prevItem", Navigation: true},
+		{Key: universal.NextItemAlt, Description: "Scroll down alternative", Action: "nextItem", Navigation: true},
+	}
+
+	return []ViewBindings{
+		{
+			View:  "",
+			Title: "Global",
+			Bindings: []Binding{
+				{Key: universal.Quit, Description: "Quit", Action: "quit"},
+				{Key: universal.OpenHelp, Description: "Help", Action: "openHelp"},
+				{Key: universal.OpenTrash, Description: "Trash", Action: "openTrash"},
+				{Key: universal.Search, Description: "Search", Action: "search"},
+				{Key: universal.CommandPalette, Description: "Command palette", Action: "commandPalette"},
+				{Key: "1", Description: "Focus years", Action: "focusYears", Navigation: true},
+				{Key: "2", Description: "Focus heat map", Action: "focusHeatmap", Navigation: true},
+				{Key: universal.NextWindow, Description: "Next window", Action: "nextWindow", Navigation: true},
+			},
+		},
+		{
+			View:  "years",
+			Title: "Years",
+			Bindings: append(listNavigation,
+				Binding{Key: universal.Select, Description: "Select year", Action: "select"},
+			),
+		},
+		{
+			View:  "heatmap",
+			Title: "Heat map",
+			Bindings: []Binding{
+				{Key: heatmap.Right, Description: "Right", Action: "right", Navigation: true},
+				{Key: heatmap.Left, Description: "Left", Action: "left", Navigation: true},
+				{Key: heatmap.Up, Description: "Up", Action: "up", Navigation: true},
+				{Key: heatmap.Down, Description: "Down", Action: "down", Navigation: true},
+				{Key: heatmap.RightAlt, Description: "Right alternative", Action: "right", Navigation: true},
+				{Key: heatmap.LeftAlt, Description: "Left alternative", Action: "left", Navigation: true},
+				{Key: heatmap.UpAlt, Description: "Up alternative", Action: "up", Navigation: true},
+				{Key: heatmap.DownAlt, Description: "Down alternative", Action: "down", Navigation: true},
+				{Key: heatmap.NextWeek, Description: "Next week", Action: "nextWeek", Navigation: true},
+				{Key: heatmap.PrevWeek, Description: "Previous week", Action: "prevWeek", Navigation: true},
+				{Key: heatmap.RowStart, Description: "Start of the row", Action: "rowStart", Navigation: true},
+				{Key: heatmap.RowEnd, Description: "End of the row", Action: "rowEnd", Navigation: true},
+				{Key: heatmap.FirstDay, Description: "First day", Action: "firstDay", Navigation: true},
+				{Key: heatmap.LastDay, Description: "Last day", Action: "lastDay", Navigation: true},
+				{Key: universal.Select, Description: "Show habits", Action: "select"},
+				{Key: universal.SelectRange, Description: "Select days", Action: "selectRange"},
+				{Key: heatmap.MarkHabitDone, Description: "Mark habit done", Action: "markHabitDone"},
+				{Key: heatmap.RenameHabit, Description: "Rename habit on the selected days", Action: "renameHabit"},
+				{Key: universal.Close, Description: "Cancel selection", Action: "close"},
+			},
+		},
+		{
+			View:  "chainpanel",
+			Title: "Habits",
+			Bindings: append(listNavigation,
+				Binding{Key: universal.SelectRange, Description: "Select habits", Action: "selectRange"},
+				Binding{Key: heatmap.ToggleHabit, Description: "Toggle habit", Action: "toggleHabit"},
+				Binding{Key: heatmap.SkipHabit, Description: "Skip habit", Action: "skipHabit"},
+				Binding{Key: heatmap.CreateHabit, Description: "Create habit", Action: "createHabit"},
+				Binding{Key: heatmap.EditHabit, Description: "Update habit", Action: "editHabit"},
+				Binding{Key: heatmap.EditNote, Description: "Edit note", Action: "editNote"},
+				Binding{Key: heatmap.DeleteHabit, Description: "Remove habit", Action: "deleteHabit"},
+				Binding{Key: heatmap.ArchiveHabit, Description: "Archive habit", Action: "archiveHabit"},
+				Binding{Key: heatmap.MoveHabitUp, Description: "Move habit up", Action: "moveHabitUp"},
+				Binding{Key: heatmap.MoveHabitDown, Description: "Move habit down", Action: "moveHabitDown"},
+				Binding{Key: heatmap.CopyPreviousDay, Description: "Copy habits from the previous day", Action: "copyPreviousDay"},
+				Binding{Key: heatmap.CopyFromDay, Description: "Copy habits from a day", Action: "copyFromDay"},
+				Binding{Key: heatmap.CopyToDays, Description: "Copy habit to days", Action: "copyToDays"},
+				Binding{Key: universal.Close, Description: "Close", Action: "close"},
+			),
+		},
+		{
+			View:  "habitpanel",
+			Title: "Habit",
+			Bindings: []Binding{
+				{Key: universal.Confirm, Description: "Confirm", Action: "confirm"},
+				{Key: universal.Close, Description: "Close", Action: "close"},
+			},
+		},
+		{
+			View:  "search",
+			Title: "Search",
+			Bindings: append(listNavigation,
+				Binding{Key: universal.Select, Description: "Show day", Action: "select"},
+				Binding{Key: universal.Confirm, Description: "Show day alternative", Action: "select", Navigation: true},
+				Binding{Key: universal.Close, Description: "Close", Action: "close"},
+			),
+		},
+		{
+			View:  "trash",
+			Title: "Trash",
+			Bindings: append(listNavigation,
+				Binding{Key: heatmap.RestoreHabit, Description: "Restore habit", Action: "restoreHabit"},
+				Binding{Key: universal.Close, Description: "Close", Action: "close"},
+			),
+		},
+		{
+			View:  "help",
+			Title: "Help",
+			Bindings: append(listNavigation,
+				Binding{Key: universal.Close, Description: "Close", Action: "close"},
+			),
+		},
+		{
+			// the letters are typed into the filter so the list is scrolled with the arrow keys only
+			View:  "palette",
+			Title: "Command palette",
+			Bindings: []Binding{
+				{Key: universal.PrevItem, Description: "Scroll up", Action: "prevItem", Navigation: true},
+				{Key: universal.NextItem, Description: "Scroll down", Action: "nextItem", Navigation: true},
+				{Key: universal.Confirm, Description: "Run action", Action: "confirm"},
+				{Key: universal.Close, Description: "Close", Action: "close"},
+			},
+		},
 	}
-	return nil
 }
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		keys  string
+		want  [][]Key
+		label string
+	}{
+		{"Given a rune should parse it", "q", [][]Key{{{Key: 'q'}}}, "q"},
+		{"Given a label should parse the key", "<C-P>", [][]Key{{{Key: gocui.KeyCtrlP}}}, "<c-p>"},
+		{"Given alternative keys should parse every one", "k <up>", [][]Key{{{Key: 'k'}}, {{Key: gocui.KeyArrowUp}}}, "k <up>"},
+		{"Given a sequence should parse the key presses", "gg", [][]Key{{{Key: 'g'}, {Key: 'g'}}}, "gg"},
+		{"Given a sequence with a label should parse the key presses", "<c-w>h", [][]Key{{{Key: gocui.KeyCtrlW}, {Key: 'h'}}}, "<c-w>h"},
+		{"Given alt with a rune should keep its case", "<a-J>", [][]Key{{{Key: 'J', Mod: gocui.ModAlt}}}, "<a-J>"},
+		{"Given alt with a key should parse the modifier", "<a-up>", [][]Key{{{Key: gocui.KeyArrowUp, Mod: gocui.ModAlt}}}, "<a-up>"},
+		{"Given alt enter should parse the alt enter key", "<a-enter>", [][]Key{{{Key: gocui.KeyAltEnter}}}, "<a-enter>"},
+		{"Given less than without a label should parse the rune", "<", [][]Key{{{Key: '<'}}}, "<"},
+		{"Given disabled should have no keys", "<disabled>", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseKeys(tt.keys)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, keys)
+			assert.Equal(t, tt.label, GetKeyLabel(tt.keys))
+		})
+	}
+
+	t.Run("Given an unknown label should fail", func(t *testing.T) {
+		_, err := ParseKeys("g<spacebar>")
+
+		assert.EqualError(t, err, "unrecognized key <spacebar> for keybinding. For permitted values see https://github.com/metagunner/habheat?tab=readme-ov-file#keybindings")
+	})
+
+	t.Run("Given an empty keybinding should fail", func(t *testing.T) {
+		_, err := ParseKeys(" ")
+
+		assert.EqualError(t, err, "empty keybinding, use <disabled> to disable it")
+	})
+}
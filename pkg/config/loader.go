@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadUserConfig loads the user config on top of the given base config.
+// The config file is created if it does not exist.
+func LoadUserConfig(configFilePath string, base *UserConfig) (*UserConfig, error) {
+	if _, err := os.Stat(configFilePath); err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		// create the config file if it does not exist
+		file, err := os.Create(configFilePath)
+		if err != nil {
+			return nil, err
+		}
+		file.Close()
+	}
+
+	content, err := os.ReadFile(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(content, base); err != nil {
+		return nil, fmt.Errorf("The config at `%s` couldn't be parsed, please inspect it before opening up an issue.\n%w", configFilePath, err)
+	}
+
+	return base, nil
+}
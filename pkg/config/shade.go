@@ -0,0 +1,40 @@
+package config
+
+import (
+	"math"
+)
+
+// Levels returns the number of status values of the color scheme.
+func (s HeatmapColorScheme) Levels() int {
+	return len(s.StatusValues)
+}
+
+// Level returns the status value level of a day, from 1 to the number of
+// levels. Days without any completed habits are level 0.
+func (s HeatmapColorScheme) Level(completedHabits int, totalNumberOfHabits int) int {
+	levels := s.Levels()
+	if completedHabits <= 0 || totalNumberOfHabits <= 0 || levels == 0 {
+		return 0
+	}
+
+	value := float64(completedHabits) / float64(totalNumberOfHabits)
+	if s.ThresholdMode == ThresholdModeCount {
+		value = float64(completedHabits)
+	}
+
+	level := 0
+	switch {
+	case len(s.Thresholds) > 0:
+		for i, threshold := range s.Thresholds {
+			if value >= threshold {
+				level = i + 1
+			}
+		}
+	case s.ThresholdMode == ThresholdModeCount:
+		level = int(value)
+	default:
+		level = int(math.Round(value * float64(levels)))
+	}
+
+	return max(1, min(level, levels))
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeatmapColorScheme_Level(t *testing.T) {
+	fiveLevels := map[int]string{1: "22", 2: "29", 3: "34", 4: "40", 5: "118"}
+	threeLevels := map[int]string{1: "22", 2: "34", 3: "118"}
+
+	tests := []struct {
+		name            string
+		scheme          HeatmapColorScheme
+		completedHabits int
+		totalHabits     int
+		level           int
+	}{
+		{"Given no completed habits should be zero", HeatmapColorScheme{StatusValues: fiveLevels}, 0, 4, 0},
+		{"Given all completed should be the last level", HeatmapColorScheme{StatusValues: fiveLevels}, 4, 4, 5},
+		{"Given half completed should be the middle level", HeatmapColorScheme{StatusValues: fiveLevels}, 2, 4, 3},
+		{"Given small ratio should be the first level", HeatmapColorScheme{StatusValues: fiveLevels}, 1, 20, 1},
+		{"Given three levels should spread evenly", HeatmapColorScheme{StatusValues: threeLevels}, 2, 3, 2},
+		{"Given ratio thresholds should use them", HeatmapColorScheme{StatusValues: threeLevels, Thresholds: []float64{0.1, 0.9, 1}}, 8, 9, 1},
+		{"Given ratio thresholds and all completed should be the last level", HeatmapColorScheme{StatusValues: threeLevels, Thresholds: []float64{0.1, 0.9, 1}}, 9, 9, 3},
+		{"Given count thresholds should use the number of completed habits", HeatmapColorScheme{StatusValues: threeLevels, Thresholds: []float64{1, 3, 6}, ThresholdMode: ThresholdModeCount}, 4, 10, 2},
+		{"Given count mode without thresholds should use one level per habit", HeatmapColorScheme{StatusValues: threeLevels, ThresholdMode: ThresholdModeCount}, 7, 10, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.level, tt.scheme.Level(tt.completedHabits, tt.totalHabits))
+		})
+	}
+}
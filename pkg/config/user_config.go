@@ -1,12 +1,70 @@
 package config
 
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/utils"
+)
+
 type UserConfig struct {
 	Gui        GuiConfig        `yaml:"gui"`
 	Keybinding KeybindingConfig `yaml:"keybinding"`
+	Journal    JournalConfig    `yaml:"journal"`
 }
 
 type GuiConfig struct {
 	Theme ThemeConfig `yaml:"theme"`
+	// First day of the week in the heat map, sunday or monday
+	WeekStartsOn string `yaml:"weekStartsOn"`
+	// Language of the month and weekday names
+	Language string `yaml:"language"`
+	// IANA time zone of the days, e.g. Europe/Istanbul. Empty for the local time zone
+	TimeZone string `yaml:"timeZone"`
+	// Hour the day starts at, the hours before it belong to the previous day
+	DayStartsAt int `yaml:"dayStartsAt"`
+}
+
+// GetWeekStart returns the first day of the week in the heat map
+func (c GuiConfig) GetWeekStart() time.Weekday {
+	if strings.EqualFold(c.WeekStartsOn, "monday") {
+		return time.Monday
+	}
+	return time.Sunday
+}
+
+// GetDayClock returns the clock that decides which day an instant belongs to
+func (c GuiConfig) GetDayClock() utils.DayClock {
+	location := time.Local
+	if c.TimeZone != "" {
+		if loaded, err := time.LoadLocation(c.TimeZone); err == nil {
+			location = loaded
+		}
+	}
+	return utils.DayClock{Location: location, StartHour: c.DayStartsAt}
+}
+
+// JournalConfig is the daily notes the habits are synced with
+type JournalConfig struct {
+	// Directory of the YYYY-MM-DD.md notes, e.g. an Obsidian vault or the
+	// journals directory of a Logseq graph. A leading ~ is the home directory.
+	Directory string `yaml:"directory"`
+	// Heading line of the checklist of the habits in a note
+	Heading string `yaml:"heading"`
+}
+
+// GetDirectory returns the directory of the notes with the ~ expanded
+func (c JournalConfig) GetDirectory() (string, error) {
+	if c.Directory != "~" && !strings.HasPrefix(c.Directory, "~/") {
+		return c.Directory, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, c.Directory[1:]), nil
 }
 
 type ThemeConfig struct {
@@ -22,37 +80,95 @@ type HeatmapColorScheme struct {
 	ZeroCompletedHabitValue string         `yaml:"zeroCompletedHabitValue"`
 	StatusValues            map[int]string `yaml:"statusValues"`
 	CursorValue             string         `yaml:"cursorValue"`
+	// Value for the vacation days and the days where every habit is skipped.
+	// The no habits value is used when it is empty.
+	SkippedValue string `yaml:"skippedValue,omitempty"`
+	// Value for the days of the selected range. The cursor value is used when
+	// it is empty.
+	SelectedValue string `yaml:"selectedValue,omitempty"`
+	// Where each status value starts, from the first level to the last. Ratios
+	// of the completed habits in the ratio mode, number of completed habits in
+	// the count mode. Levels are spread evenly when it is empty.
+	Thresholds    []float64     `yaml:"thresholds,omitempty"`
+	ThresholdMode ThresholdMode `yaml:"thresholdMode,omitempty"`
 }
 
+// GetSkippedValue returns the value of the vacation days and the days where
+// every habit is skipped
+func (s HeatmapColorScheme) GetSkippedValue() string {
+	if s.SkippedValue == "" {
+		return s.NoHabitsValue
+	}
+	return s.SkippedValue
+}
+
+// GetSelectedValue returns the value of the days of the selected range
+func (s HeatmapColorScheme) GetSelectedValue() string {
+	if s.SelectedValue == "" {
+		return s.CursorValue
+	}
+	return s.SelectedValue
+}
+
+type ThresholdMode string
+
+const (
+	ThresholdModeRatio ThresholdMode = "ratio"
+	ThresholdModeCount ThresholdMode = "count"
+)
+
 type KeybindingConfig struct {
 	Universal KeybindingUniversalConfig `yaml:"universal"`
 	Heatmap   KeybindingHeatmapConfig   `yaml:"heatmap"`
 }
 
 type KeybindingUniversalConfig struct {
-	Quit        string `yaml:"quit"`
-	PrevItem    string `yaml:"prevItem"`
-	NextItem    string `yaml:"nextItem"`
-	PrevItemAlt string `yaml:"prevItemAlt"`
-	NextItemAlt string `yaml:"nextItemAlt"`
-	Select      string `yaml:"select"`
-	Confirm     string `yaml:"confirm"`
-	Close       string `yaml:"close"`
+	Quit           string `yaml:"quit"`
+	PrevItem       string `yaml:"prevItem"`
+	NextItem       string `yaml:"nextItem"`
+	PrevItemAlt    string `yaml:"prevItemAlt"`
+	NextItemAlt    string `yaml:"nextItemAlt"`
+	Select         string `yaml:"select"`
+	Confirm        string `yaml:"confirm"`
+	Close          string `yaml:"close"`
+	OpenHelp       string `yaml:"openHelp"`
+	OpenTrash      string `yaml:"openTrash"`
+	SelectRange    string `yaml:"selectRange"`
+	Search         string `yaml:"search"`
+	CommandPalette string `yaml:"commandPalette"`
+	NextWindow     string `yaml:"nextWindow"`
 }
 
 type KeybindingHeatmapConfig struct {
-	Right       string `yaml:"right"`
-	Left        string `yaml:"left"`
-	Up          string `yaml:"up"`
-	Down        string `yaml:"down"`
-	RightAlt    string `yaml:"rightAlt"`
-	LeftAlt     string `yaml:"leftAlt"`
-	UpAlt       string `yaml:"upAlt"`
-	DownAlt     string `yaml:"downAlt"`
-	EditHabit   string `yaml:"editHabit"`
-	ToggleHabit string `yaml:"toggleHabit"`
-	CreateHabit string `yaml:"createHabit"`
-	DeleteHabit string `yaml:"deleteHabit"`
+	Right           string `yaml:"right"`
+	Left            string `yaml:"left"`
+	Up              string `yaml:"up"`
+	Down            string `yaml:"down"`
+	RightAlt        string `yaml:"rightAlt"`
+	LeftAlt         string `yaml:"leftAlt"`
+	UpAlt           string `yaml:"upAlt"`
+	DownAlt         string `yaml:"downAlt"`
+	EditHabit       string `yaml:"editHabit"`
+	ToggleHabit     string `yaml:"toggleHabit"`
+	CreateHabit     string `yaml:"createHabit"`
+	DeleteHabit     string `yaml:"deleteHabit"`
+	ArchiveHabit    string `yaml:"archiveHabit"`
+	SkipHabit       string `yaml:"skipHabit"`
+	MoveHabitUp     string `yaml:"moveHabitUp"`
+	MoveHabitDown   string `yaml:"moveHabitDown"`
+	CopyPreviousDay string `yaml:"copyPreviousDay"`
+	CopyFromDay     string `yaml:"copyFromDay"`
+	CopyToDays      string `yaml:"copyToDays"`
+	RenameHabit     string `yaml:"renameHabit"`
+	MarkHabitDone   string `yaml:"markHabitDone"`
+	EditNote        string `yaml:"editNote"`
+	RestoreHabit    string `yaml:"restoreHabit"`
+	NextWeek        string `yaml:"nextWeek"`
+	PrevWeek        string `yaml:"prevWeek"`
+	RowStart        string `yaml:"rowStart"`
+	RowEnd          string `yaml:"rowEnd"`
+	FirstDay        string `yaml:"firstDay"`
+	LastDay         string `yaml:"lastDay"`
 }
 
 const (
@@ -63,6 +179,8 @@ const (
 func GetDefaultConfig() *UserConfig {
 	return &UserConfig{
 		Gui: GuiConfig{
+			WeekStartsOn: "sunday",
+			Language:     "en",
 			Theme: ThemeConfig{
 				ActiveBorderColor:   []string{"green", "bold"},
 				InactiveBorderColor: []string{"default"},
@@ -70,72 +188,106 @@ func GetDefaultConfig() *UserConfig {
 				ColorSchemes: map[string]HeatmapColorScheme{
 					"green": {
 						InvalidDayValue: "  ", NoHabitsValue: "  ", ZeroCompletedHabitValue: "  ", StatusValues: map[int]string{
-							1: "\033[48;5;22m" + space + end,
-							2: "\033[48;5;29m" + space + end,
-							3: "\033[48;5;34m" + space + end,
-							4: "\033[48;5;40m" + space + end,
-							5: "\033[48;5;118m" + space + end,
+							1: "22",
+							2: "29",
+							3: "34",
+							4: "40",
+							5: "118",
 						},
-						CursorValue: "\033[48;5;196m" + space + end,
+						CursorValue:   "196",
+						SkippedValue:  "238",
+						SelectedValue: "208",
 					},
 					"purple": {
 						InvalidDayValue: "  ", NoHabitsValue: "  ", ZeroCompletedHabitValue: "  ", StatusValues: map[int]string{
-							1: "\033[48;5;55m" + space + end,
-							2: "\033[48;5;92m" + space + end,
-							3: "\033[48;5;93m" + space + end,
-							4: "\033[48;5;129m" + space + end,
-							5: "\033[48;5;135m" + space + end,
+							1: "55",
+							2: "92",
+							3: "93",
+							4: "129",
+							5: "135",
 						},
-						CursorValue: "\033[48;5;196m" + space + end,
+						CursorValue:   "196",
+						SkippedValue:  "238",
+						SelectedValue: "208",
 					},
 					"yellow": {
 						InvalidDayValue: "  ", NoHabitsValue: "  ", ZeroCompletedHabitValue: "  ", StatusValues: map[int]string{
-							1: "\033[48;5;142m" + space + end,
-							2: "\033[48;5;178m" + space + end,
-							3: "\033[48;5;184m" + space + end,
-							4: "\033[48;5;220m" + space + end,
-							5: "\033[48;5;226m" + space + end,
+							1: "142",
+							2: "178",
+							3: "184",
+							4: "220",
+							5: "226",
 						},
-						CursorValue: "\033[48;5;196m" + space + end,
+						CursorValue:   "196",
+						SkippedValue:  "238",
+						SelectedValue: "208",
 					},
 					"ice": {
 						InvalidDayValue: "  ", NoHabitsValue: "  ", ZeroCompletedHabitValue: "  ", StatusValues: map[int]string{
-							1: "\033[48;5;61m" + space + end,
-							2: "\033[48;5;67m" + space + end,
-							3: "\033[48;5;68m" + space + end,
-							4: "\033[48;5;74m" + space + end,
-							5: "\033[48;5;75m" + space + end,
+							1: "61",
+							2: "67",
+							3: "68",
+							4: "74",
+							5: "75",
 						},
-						CursorValue: "\033[48;5;196m" + space + end,
+						CursorValue:   "196",
+						SkippedValue:  "238",
+						SelectedValue: "208",
 					},
 				},
 			},
 		},
 		Keybinding: KeybindingConfig{
 			Universal: KeybindingUniversalConfig{
-				Quit:        "q",
-				PrevItem:    "<up>",
-				NextItem:    "<down>",
-				PrevItemAlt: "k",
-				NextItemAlt: "j",
-				Select:      "<space>",
-				Confirm:     "<enter>",
-				Close:       "<esc>",
+				Quit:           "q",
+				PrevItem:       "<up>",
+				NextItem:       "<down>",
+				PrevItemAlt:    "k",
+				NextItemAlt:    "j",
+				Select:         "<space>",
+				Confirm:        "<enter>",
+				Close:          "<esc>",
+				OpenHelp:       "?",
+				OpenTrash:      "t",
+				SelectRange:    "v",
+				Search:         "/",
+				CommandPalette: ": <c-p>",
+				NextWindow:     "<tab>",
 			},
 			Heatmap: KeybindingHeatmapConfig{
-				Right:       "l",
-				Left:        "h",
-				Up:          "k",
-				Down:        "j",
-				RightAlt:    "<right>",
-				LeftAlt:     "<left>",
-				UpAlt:       "<up>",
-				DownAlt:     "<down>",
-				EditHabit:   "u",
-				ToggleHabit: "<space>",
-				CreateHabit: "n",
-				DeleteHabit: "r",
+				Right:           "l",
+				Left:            "h",
+				Up:              "k",
+				Down:            "j",
+				RightAlt:        "<right>",
+				LeftAlt:         "<left>",
+				UpAlt:           "<up>",
+				DownAlt:         "<down>",
+				EditHabit:       "u",
+				ToggleHabit:     "<space>",
+				CreateHabit:     "n",
+				DeleteHabit:     "r",
+				ArchiveHabit:    "a",
+				SkipHabit:       "s",
+				MoveHabitUp:     "K",
+				MoveHabitDown:   "J",
+				CopyPreviousDay: "p",
+				CopyFromDay:     "c",
+				CopyToDays:      "C",
+				RenameHabit:     "R",
+				MarkHabitDone:   "x",
+				EditNote:        "N",
+				RestoreHabit:    "r",
+				NextWeek:        "w",
+				PrevWeek:        "b",
+				RowStart:        "0",
+				RowEnd:          "$",
+				FirstDay:        "gg",
+				LastDay:         "G",
 			},
 		},
+		Journal: JournalConfig{
+			Heading: "## Habits",
+		},
 	}
 }
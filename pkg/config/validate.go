@@ -0,0 +1,256 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/samber/lo"
+)
+
+// ValidationError lists every problem found in the user config.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "found %d problem(s) in the config:", len(e.Problems))
+	for _, problem := range e.Problems {
+		fmt.Fprintf(&b, "\n  - %s", problem)
+	}
+	return b.String()
+}
+
+// Validate checks the user config and reports all the problems at once.
+// Returns nil when the config is valid.
+func Validate(c *UserConfig) error {
+	problems := []string{}
+	problems = append(problems, validateTheme(c.Gui.Theme)...)
+	problems = append(problems, validateLocale(c.Gui)...)
+	problems = append(problems, validateKeys("keybinding.universal", c.Keybinding.Universal)...)
+	problems = append(problems, validateKeys("keybinding.heatmap", c.Keybinding.Heatmap)...)
+	problems = append(problems, validateDuplicateBindings(c.Keybinding)...)
+	if strings.TrimSpace(c.Journal.Heading) == "" {
+		problems = append(problems, "journal.heading: the heading of the habits can't be empty")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func validateLocale(c GuiConfig) []string {
+	problems := []string{}
+	if weekStart := strings.ToLower(c.WeekStartsOn); weekStart != "sunday" && weekStart != "monday" {
+		problems = append(problems, fmt.Sprintf("gui.weekStartsOn: unknown day %q, use sunday or monday", c.WeekStartsOn))
+	}
+	if _, ok := utils.GetLocale(c.Language); !ok {
+		problems = append(problems, fmt.Sprintf("gui.language: unsupported language %q, supported languages are %s", c.Language, strings.Join(utils.GetLanguages(), ", ")))
+	}
+	if _, err := time.LoadLocation(c.TimeZone); err != nil {
+		problems = append(problems, fmt.Sprintf("gui.timeZone: unknown time zone %q, use an IANA name like Europe/Istanbul", c.TimeZone))
+	}
+	if c.DayStartsAt < 0 || c.DayStartsAt > 23 {
+		problems = append(problems, fmt.Sprintf("gui.dayStartsAt: %d, the hour must be between 0 and 23", c.DayStartsAt))
+	}
+	return problems
+}
+
+func validateTheme(theme ThemeConfig) []string {
+	problems := []string{}
+	if _, ok := theme.ColorSchemes[theme.Selected]; !ok {
+		problems = append(problems, fmt.Sprintf("gui.theme.selected: unknown color scheme %q, available schemes are %s", theme.Selected, strings.Join(getSchemeNames(theme), ", ")))
+	}
+
+	for _, name := range getSchemeNames(theme) {
+		scheme := theme.ColorSchemes[name]
+		path := "gui.theme.colorSchemes." + name
+		problems = append(problems, validateLevels(path, scheme)...)
+		problems = append(problems, validateCellValue(path+".invalidDayValue", scheme.InvalidDayValue)...)
+		problems = append(problems, validateCellValue(path+".noHabitsValue", scheme.NoHabitsValue)...)
+		problems = append(problems, validateCellValue(path+".zeroCompletedHabitValue", scheme.ZeroCompletedHabitValue)...)
+		problems = append(problems, validateCellValue(path+".cursorValue", scheme.CursorValue)...)
+		problems = append(problems, validateCellValue(path+".skippedValue", scheme.SkippedValue)...)
+		problems = append(problems, validateCellValue(path+".selectedValue", scheme.SelectedValue)...)
+		levels := lo.Keys(scheme.StatusValues)
+		sort.Ints(levels)
+		for _, level := range levels {
+			problems = append(problems, validateCellValue(fmt.Sprintf("%s.statusValues.%d", path, level), scheme.StatusValues[level])...)
+		}
+	}
+
+	problems = append(problems, validateColors("gui.theme.activeBorderColor", theme.ActiveBorderColor)...)
+	problems = append(problems, validateColors("gui.theme.inactiveBorderColor", theme.InactiveBorderColor)...)
+	return problems
+}
+
+// status values must be numbered from 1 without gaps, the thresholds must match them
+func validateLevels(path string, scheme HeatmapColorScheme) []string {
+	problems := []string{}
+	if len(scheme.StatusValues) == 0 {
+		problems = append(problems, fmt.Sprintf("%s.statusValues: at least one status value is required", path))
+	}
+
+	maxLevel := 0
+	for level := range scheme.StatusValues {
+		if level < 1 {
+			problems = append(problems, fmt.Sprintf("%s.statusValues: levels start from 1, found %d", path, level))
+		}
+		maxLevel = max(maxLevel, level)
+	}
+	for i := 1; i <= maxLevel; i++ {
+		if _, ok := scheme.StatusValues[i]; !ok {
+			problems = append(problems, fmt.Sprintf("%s.statusValues: missing value for %d", path, i))
+		}
+	}
+
+	mode := scheme.ThresholdMode
+	if mode != "" && mode != ThresholdModeRatio && mode != ThresholdModeCount {
+		problems = append(problems, fmt.Sprintf("%s.thresholdMode: unknown mode %q, use %s or %s", path, mode, ThresholdModeRatio, ThresholdModeCount))
+	}
+
+	thresholds := scheme.Thresholds
+	if len(thresholds) == 0 {
+		return problems
+	}
+	if len(thresholds) != maxLevel {
+		problems = append(problems, fmt.Sprintf("%s.thresholds: %d thresholds for %d status values", path, len(thresholds), maxLevel))
+	}
+	for i, threshold := range thresholds {
+		if i > 0 && threshold <= thresholds[i-1] {
+			problems = append(problems, fmt.Sprintf("%s.thresholds: thresholds must be in ascending order", path))
+			break
+		}
+	}
+	for _, threshold := range thresholds {
+		if mode == ThresholdModeCount && threshold < 1 {
+			problems = append(problems, fmt.Sprintf("%s.thresholds: %v, thresholds in the count mode must be at least 1", path, threshold))
+		} else if mode != ThresholdModeCount && (threshold <= 0 || threshold > 1) {
+			problems = append(problems, fmt.Sprintf("%s.thresholds: %v, thresholds in the ratio mode must be greater than 0 and at most 1", path, threshold))
+		}
+	}
+	return problems
+}
+
+// values that look like a color must be a valid one, anything else is printed as it is
+func validateCellValue(path string, value string) []string {
+	if _, ok := ParseColor(value); ok {
+		return nil
+	}
+	trimmed := strings.TrimSpace(value)
+	if strings.HasPrefix(trimmed, "#") {
+		return []string{fmt.Sprintf("%s: invalid hex color %q, use the #rrggbb format", path, value)}
+	}
+	if _, err := strconv.Atoi(trimmed); err == nil {
+		return []string{fmt.Sprintf("%s: invalid color %q, the palette index must be between 0 and 255", path, value)}
+	}
+	return nil
+}
+
+func validateColors(path string, colors []string) []string {
+	problems := []string{}
+	for _, color := range colors {
+		if _, ok := gocuiColorMap[color]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: unknown color %q. For permitted values see %s", path, color, "https://github.com/metagunner/habheat?tab=readme-ov-file#color-attributes"))
+		}
+	}
+	return problems
+}
+
+// validates every string field of the given keybinding config struct
+func validateKeys(path string, keys interface{}) []string {
+	problems := []string{}
+	value := reflect.ValueOf(keys)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.Type.Kind() != reflect.String {
+			continue
+		}
+		if _, err := ParseKeys(value.Field(i).String()); err != nil {
+			problems = append(problems, fmt.Sprintf("%s.%s: %s", path, field.Tag.Get("yaml"), err))
+		}
+	}
+	return problems
+}
+
+// reports the keys that are bound to more than one action in the same view and
+// the keys that start a longer sequence, e.g. g and gg, as the longer one could
+// never be typed. Global keybindings are active in every view so they are
+// checked as well.
+func validateDuplicateBindings(c KeybindingConfig) []string {
+	type sequence struct {
+		keys        []Key
+		label       string
+		description string
+		// bound by the view itself, the problems among the global ones are reported once
+		isViewKey bool
+	}
+
+	problems := []string{}
+	viewBindings := c.GetViewBindings()
+	global := viewBindings[0].Bindings
+	for _, group := range viewBindings {
+		bindings := group.Bindings
+		if group.View != "" {
+			bindings = append(append([]Binding{}, global...), bindings...)
+		}
+
+		sequences := []sequence{}
+		for i, binding := range bindings {
+			keys, err := ParseKeys(binding.Key)
+			if err != nil {
+				continue
+			}
+			for _, key := range keys {
+				sequences = append(sequences, sequence{
+					keys:        key,
+					label:       GetSequenceLabel(key),
+					description: binding.Description,
+					isViewKey:   group.View == "" || i >= len(global),
+				})
+			}
+		}
+
+		descriptionsByKey := map[string][]string{}
+		viewKeys := map[string]bool{}
+		labels := []string{}
+		for _, seq := range sequences {
+			if _, ok := descriptionsByKey[seq.label]; !ok {
+				labels = append(labels, seq.label)
+			}
+			descriptionsByKey[seq.label] = append(descriptionsByKey[seq.label], seq.description)
+			viewKeys[seq.label] = viewKeys[seq.label] || seq.isViewKey
+		}
+		for _, label := range labels {
+			if descriptions := descriptionsByKey[label]; len(descriptions) > 1 && viewKeys[label] {
+				problems = append(problems, fmt.Sprintf("keybinding: %s is bound to more than one action in the %s view: %s", label, strings.ToLower(group.Title), strings.Join(descriptions, ", ")))
+			}
+		}
+
+		for _, prefix := range sequences {
+			for _, seq := range sequences {
+				if len(prefix.keys) >= len(seq.keys) || !(prefix.isViewKey || seq.isViewKey) || !slices.Equal(prefix.keys, seq.keys[:len(prefix.keys)]) {
+					continue
+				}
+				problems = append(problems, fmt.Sprintf("keybinding: %s is the start of %s in the %s view: %s, %s", prefix.label, seq.label, strings.ToLower(group.Title), prefix.description, seq.description))
+			}
+		}
+	}
+	return problems
+}
+
+func getSchemeNames(theme ThemeConfig) []string {
+	names := make([]string, 0, len(theme.ColorSchemes))
+	for name := range theme.ColorSchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
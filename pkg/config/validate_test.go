@@ -0,0 +1,148 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("Given default config should succeed", func(t *testing.T) {
+		err := Validate(GetDefaultConfig())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Given invalid config should report all problems", func(t *testing.T) {
+		c := GetDefaultConfig()
+		c.Gui.Theme.Selected = "red"
+		delete(c.Gui.Theme.ColorSchemes["ice"].StatusValues, 3)
+		c.Gui.Theme.ActiveBorderColor = []string{"green", "pink"}
+		c.Keybinding.Heatmap.ToggleHabit = "<spacebar>"
+		c.Keybinding.Heatmap.DeleteHabit = "n"
+
+		err := Validate(c)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []string{
+			`gui.theme.selected: unknown color scheme "red", available schemes are green, ice, purple, yellow`,
+			`gui.theme.colorSchemes.ice.statusValues: missing value for 3`,
+			`gui.theme.activeBorderColor: unknown color "pink". For permitted values see https://github.com/metagunner/habheat?tab=readme-ov-file#color-attributes`,
+			`keybinding.heatmap.toggleHabit: unrecognized key <spacebar> for keybinding. For permitted values see https://github.com/metagunner/habheat?tab=readme-ov-file#keybindings`,
+			`keybinding: n is bound to more than one action in the habits view: Create habit, Remove habit`,
+		}, validationErr.Problems)
+	})
+
+	t.Run("Given invalid colors in color scheme should report them", func(t *testing.T) {
+		c := GetDefaultConfig()
+		c.Gui.Theme.ColorSchemes["custom"] = HeatmapColorScheme{
+			InvalidDayValue:         "  ",
+			NoHabitsValue:           "🚫",
+			ZeroCompletedHabitValue: "#12345",
+			StatusValues:            map[int]string{1: "#216e39", 2: "green", 3: "300", 4: "\033[48;5;40m  \033[0m", 5: "118"},
+			CursorValue:             "196",
+		}
+
+		err := Validate(c)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []string{
+			`gui.theme.colorSchemes.custom.zeroCompletedHabitValue: invalid hex color "#12345", use the #rrggbb format`,
+			`gui.theme.colorSchemes.custom.statusValues.3: invalid color "300", the palette index must be between 0 and 255`,
+		}, validationErr.Problems)
+	})
+
+	t.Run("Given unknown week start, language and time zone should report them", func(t *testing.T) {
+		c := GetDefaultConfig()
+		c.Gui.WeekStartsOn = "friday"
+		c.Gui.Language = "xx"
+		c.Gui.TimeZone = "Mars/Olympus"
+		c.Gui.DayStartsAt = 24
+
+		err := Validate(c)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []string{
+			`gui.weekStartsOn: unknown day "friday", use sunday or monday`,
+			`gui.language: unsupported language "xx", supported languages are de, en, es, fr, tr`,
+			`gui.timeZone: unknown time zone "Mars/Olympus", use an IANA name like Europe/Istanbul`,
+			`gui.dayStartsAt: 24, the hour must be between 0 and 23`,
+		}, validationErr.Problems)
+	})
+
+	t.Run("Given empty journal heading should report it", func(t *testing.T) {
+		c := GetDefaultConfig()
+		c.Journal.Heading = "  "
+
+		err := Validate(c)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []string{`journal.heading: the heading of the habits can't be empty`}, validationErr.Problems)
+	})
+
+	t.Run("Given invalid thresholds should report them", func(t *testing.T) {
+		c := GetDefaultConfig()
+		c.Gui.Theme.ColorSchemes["custom"] = HeatmapColorScheme{
+			StatusValues:  map[int]string{1: "22", 2: "34", 4: "118"},
+			Thresholds:    []float64{1, 0.5, 2},
+			ThresholdMode: "percent",
+		}
+
+		err := Validate(c)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []string{
+			`gui.theme.colorSchemes.custom.statusValues: missing value for 3`,
+			`gui.theme.colorSchemes.custom.thresholdMode: unknown mode "percent", use ratio or count`,
+			`gui.theme.colorSchemes.custom.thresholds: 3 thresholds for 4 status values`,
+			`gui.theme.colorSchemes.custom.thresholds: thresholds must be in ascending order`,
+			`gui.theme.colorSchemes.custom.thresholds: 2, thresholds in the ratio mode must be greater than 0 and at most 1`,
+		}, validationErr.Problems)
+	})
+
+	t.Run("Given global key bound in a view should report duplicate", func(t *testing.T) {
+		c := GetDefaultConfig()
+		c.Keybinding.Heatmap.CreateHabit = "q"
+
+		err := Validate(c)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []string{
+			`keybinding: q is bound to more than one action in the habits view: Quit, Create habit`,
+		}, validationErr.Problems)
+	})
+
+	t.Run("Given alternative key bound twice should report duplicate", func(t *testing.T) {
+		c := GetDefaultConfig()
+		c.Keybinding.Heatmap.CreateHabit = "n <a-n>"
+		c.Keybinding.Heatmap.EditHabit = "u <a-n>"
+
+		err := Validate(c)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []string{
+			`keybinding: <a-n> is bound to more than one action in the habits view: Create habit, Update habit`,
+		}, validationErr.Problems)
+	})
+
+	t.Run("Given key that starts a sequence should report it", func(t *testing.T) {
+		c := GetDefaultConfig()
+		c.Keybinding.Heatmap.MarkHabitDone = "xx"
+		c.Keybinding.Heatmap.EditHabit = "q<a-u>"
+
+		err := Validate(c)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []string{
+			`keybinding: q is the start of q<a-u> in the habits view: Quit, Update habit`,
+		}, validationErr.Problems)
+	})
+}
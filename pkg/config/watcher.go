@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// editors usually write a file in several steps, events that come in this
+// duration are reported as a single change
+const watchDebounce = 200 * time.Millisecond
+
+// Watcher calls onChange when the watched config file is modified.
+type Watcher struct {
+	path     string
+	onChange func()
+	done     chan struct{}
+	once     sync.Once
+	mu       sync.Mutex
+	timer    *time.Timer
+	// inotify file descriptor, only used on linux
+	file *os.File
+}
+
+// WatchUserConfig starts watching the given config file. Close must be called
+// to stop watching.
+func WatchUserConfig(configFilePath string, onChange func()) (*Watcher, error) {
+	w := &Watcher{
+		path:     filepath.Clean(configFilePath),
+		onChange: onChange,
+		done:     make(chan struct{}),
+	}
+	if err := w.start(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Watcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		err = w.stop()
+	})
+	return err
+}
+
+func (w *Watcher) notify() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(watchDebounce, func() {
+		select {
+		case <-w.done:
+		default:
+			w.onChange()
+		}
+	})
+}
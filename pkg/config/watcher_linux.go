@@ -0,0 +1,56 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// The directory is watched instead of the file because most editors save a
+// file by writing a new one and renaming it over the old one.
+func (w *Watcher) start() error {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return err
+	}
+
+	mask := uint32(unix.IN_CLOSE_WRITE | unix.IN_MOVED_TO | unix.IN_CREATE)
+	if _, err := unix.InotifyAddWatch(fd, filepath.Dir(w.path), mask); err != nil {
+		unix.Close(fd)
+		return err
+	}
+
+	// a non blocking file uses the runtime poller so Close interrupts Read
+	file := os.NewFile(uintptr(fd), "inotify")
+	w.file = file
+	go w.readEvents(file)
+	return nil
+}
+
+func (w *Watcher) stop() error {
+	return w.file.Close()
+}
+
+func (w *Watcher) readEvents(file *os.File) {
+	fileName := []byte(filepath.Base(w.path))
+	buf := make([]byte, 4096)
+	for {
+		n, err := file.Read(buf)
+		if err != nil {
+			return
+		}
+
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameStart := offset + unix.SizeofInotifyEvent
+			name := bytes.TrimRight(buf[nameStart:nameStart+int(event.Len)], "\x00")
+			if bytes.Equal(name, fileName) {
+				w.notify()
+			}
+			offset = nameStart + int(event.Len)
+		}
+	}
+}
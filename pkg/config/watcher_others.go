@@ -0,0 +1,45 @@
+//go:build !linux
+
+package config
+
+import (
+	"os"
+	"time"
+)
+
+const watchPollInterval = time.Second
+
+// Polls the modification time of the config file on the platforms that
+// don't have inotify.
+func (w *Watcher) start() error {
+	lastModTime := getModTime(w.path)
+	go func() {
+		ticker := time.NewTicker(watchPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+				modTime := getModTime(w.path)
+				if !modTime.Equal(lastModTime) {
+					lastModTime = modTime
+					w.notify()
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (w *Watcher) stop() error {
+	return nil
+}
+
+func getModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchUserConfig(t *testing.T) {
+	configFilePath := filepath.Join(t.TempDir(), "config.yml")
+	assert.NoError(t, os.WriteFile(configFilePath, []byte(""), 0o600))
+
+	changed := make(chan struct{}, 1)
+	watcher, err := WatchUserConfig(configFilePath, func() {
+		changed <- struct{}{}
+	})
+	assert.NoError(t, err)
+	defer watcher.Close()
+
+	// the poller on other platforms compares modification times
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, os.WriteFile(configFilePath, []byte("gui:\n"), 0o600))
+
+	select {
+	case <-changed:
+	case <-time.After(3 * time.Second):
+		t.Fatal("config change is not reported")
+	}
+}
@@ -5,64 +5,273 @@ import (
 	"database/sql"
 	"embed"
 	"fmt"
+	"io/fs"
+	"log"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/metagunner/habheat/pkg/app"
 	"github.com/pressly/goose/v3"
 )
 
 // DB represents the database connection.
 type DB struct {
 	db *sql.DB
+	// Pool of the transactions that read before they write, see beginWrite.
+	writeDB *sql.DB
 	// Datasource name.
 	DSN string
+	// Skips running the pending migrations on open, the migrations can be
+	// run with Up instead.
+	SkipMigrations bool
+	// Logs the applied migrations and the backups.
+	Logger *log.Logger
+	// Whether the habits are searched with the FTS5 index, SQLite has FTS5
+	// when it is built with the sqlite_fts5 tag.
+	fullTextSearch bool
 }
 
 //go:embed migration/*.sql
 var embedMigrations embed.FS
 
+// ErrSchemaTooNew is returned when the database was migrated by a newer
+// version of habheat.
+var ErrSchemaTooNew = app.Errorf(app.ECONFLICT, "The database was created by a newer version of habheat, update habheat to open it.")
+
 // NewDB returns a new instance of DB associated with the given datasource name.
 func NewDB(dsn string) *DB {
 	db := &DB{
-		DSN: dsn,
+		DSN:    dsn,
+		Logger: log.Default(),
 	}
 	return db
 }
 
-// Open creates a new DB for the given connection string.
+// Open creates a new DB for the given connection string and runs the pending
+// migrations unless SkipMigrations is set.
 func (db *DB) Open() (err error) {
 	if db.DSN == "" {
 		return fmt.Errorf("dsn required")
 	}
 
-	if db.DSN != ":memory:" {
-		if err := os.MkdirAll(filepath.Dir(db.DSN), 0o700); err != nil {
+	if path, ok := db.filePath(); ok {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
 			return err
 		}
 	}
 
-	if db.db, err = sql.Open("sqlite3", db.DSN); err != nil {
+	if db.db, err = sql.Open("sqlite3", db.connectionString()); err != nil {
 		return err
 	}
+	// the connections of a private in memory database don't share it
+	db.writeDB = db.db
+	if db.DSN != ":memory:" {
+		if db.writeDB, err = sql.Open("sqlite3", db.connectionString()+"&_txlock=immediate"); err != nil {
+			return err
+		}
+	}
 
-	if _, err := db.db.Exec(`PRAGMA foreign_keys = ON;`); err != nil {
-		return fmt.Errorf("foreign keys pragma: %w", err)
+	ctx := context.Background()
+	if err := db.checkSearch(ctx); err != nil {
+		return fmt.Errorf("search index: %w", err)
 	}
 
-	goose.SetBaseFS(embedMigrations)
-	if err := goose.SetDialect("sqlite3"); err != nil {
-		panic(err)
+	if db.SkipMigrations {
+		// still refuse a newer schema so it is not modified by this version,
+		// the index is created when the schema is migrated
+		current, latest, err := db.Version(ctx)
+		if err != nil {
+			return err
+		}
+		if current < latest {
+			// the notes are searched with LIKE until the index is created
+			db.fullTextSearch = false
+			return nil
+		}
+	} else if _, err := db.Up(ctx); err != nil {
+		return fmt.Errorf("migrate database: %w", err)
 	}
 
-	if err := goose.Up(db.db, "migration"); err != nil {
-		panic(err)
+	if err := db.initSearch(ctx); err != nil {
+		return fmt.Errorf("search index: %w", err)
 	}
 
 	return nil
 }
 
+// the pragmas are set by the driver on every connection of the pool, the API
+// server writes from many connections at once.
+const connectionParams = "_busy_timeout=5000&_journal_mode=WAL&_foreign_keys=on"
+
+// Begins a transaction that takes the write lock when it begins. A transaction
+// that reads before it writes would fail with "database is locked" instead of
+// waiting for the busy timeout when another connection writes in between, the
+// other transactions take the lock when they first write.
+func (db *DB) beginWrite(ctx context.Context) (*sql.Tx, error) {
+	return db.writeDB.BeginTx(ctx, nil)
+}
+
+// Returns the DSN with the connection params, the DSN may already have params,
+// e.g. "file::memory:?cache=shared".
+func (db *DB) connectionString() string {
+	if strings.Contains(db.DSN, "?") {
+		return db.DSN + "&" + connectionParams
+	}
+	return db.DSN + "?" + connectionParams
+}
+
+func (db *DB) provider() (*goose.Provider, error) {
+	migrations, err := fs.Sub(embedMigrations, "migration")
+	if err != nil {
+		return nil, err
+	}
+	return goose.NewProvider(goose.DialectSQLite3, db.db, migrations)
+}
+
+// Version returns the schema version of the database and the latest version
+// known by this binary. Returns ErrSchemaTooNew if the database is newer.
+func (db *DB) Version(ctx context.Context) (current int64, latest int64, err error) {
+	provider, err := db.provider()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if current, err = provider.GetDBVersion(ctx); err != nil {
+		return 0, 0, err
+	}
+	sources := provider.ListSources()
+	latest = sources[len(sources)-1].Version
+
+	if current > latest {
+		return current, latest, ErrSchemaTooNew
+	}
+	return current, latest, nil
+}
+
+// Status returns the state of every known migration.
+func (db *DB) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	if _, _, err := db.Version(ctx); err != nil {
+		return nil, err
+	}
+
+	provider, err := db.provider()
+	if err != nil {
+		return nil, err
+	}
+	return provider.Status(ctx)
+}
+
+// Up runs the pending migrations. The database file is backed up before
+// migrating an existing schema.
+func (db *DB) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	current, latest, err := db.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if current == latest {
+		return nil, nil
+	}
+
+	if current > 0 {
+		if err := db.backup(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+
+	provider, err := db.provider()
+	if err != nil {
+		return nil, err
+	}
+	results, err := provider.Up(ctx)
+	db.logResults(results)
+	return results, err
+}
+
+// Down rolls back the latest applied migration. The database file is backed
+// up first.
+func (db *DB) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	current, _, err := db.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if current == 0 {
+		return nil, app.Errorf(app.EINVALID, "There is no migration to roll back.")
+	}
+
+	if err := db.backup(ctx, current); err != nil {
+		return nil, err
+	}
+
+	provider, err := db.provider()
+	if err != nil {
+		return nil, err
+	}
+	result, err := provider.Down(ctx)
+	if result != nil {
+		db.logResults([]*goose.MigrationResult{result})
+	}
+	return result, err
+}
+
+// Copies the database next to the database file, e.g. habheat.db.20240627105906-20240801T120000.bak.
+// In memory databases are not backed up.
+func (db *DB) backup(ctx context.Context, version int64) error {
+	path, ok := db.filePath()
+	if !ok {
+		return nil
+	}
+
+	backupPath := fmt.Sprintf("%s.%d-%s.bak", path, version, time.Now().UTC().Format("20060102T150405"))
+	if _, err := db.db.ExecContext(ctx, `VACUUM INTO ?`, backupPath); err != nil {
+		return fmt.Errorf("backup database: %w", err)
+	}
+	db.Logger.Printf("backed up the database to %s", backupPath)
+	return nil
+}
+
+// Returns the path of the database file of the DSN, false for the in memory
+// databases. The DSN is a path or a URI, e.g. "file:/data/habheat.db?mode=rw".
+func (db *DB) filePath() (string, bool) {
+	if db.DSN == ":memory:" || strings.HasPrefix(db.DSN, "file::memory:") {
+		return "", false
+	}
+
+	path, query, _ := strings.Cut(db.DSN, "?")
+	if !strings.HasPrefix(path, "file:") {
+		return path, true
+	}
+	if params, err := url.ParseQuery(query); err == nil && params.Get("mode") == "memory" {
+		return "", false
+	}
+	path = strings.TrimPrefix(path, "file:")
+	// the authority of file://localhost/data/habheat.db is empty or localhost
+	if rest, ok := strings.CutPrefix(path, "//"); ok {
+		if i := strings.Index(rest, "/"); i >= 0 {
+			path = rest[i:]
+		}
+	}
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+	return path, true
+}
+
+func (db *DB) logResults(results []*goose.MigrationResult) {
+	for _, result := range results {
+		db.Logger.Print(result)
+	}
+}
+
 func (db *DB) Close() error {
+	if db.writeDB != nil && db.writeDB != db.db {
+		if err := db.writeDB.Close(); err != nil {
+			return err
+		}
+	}
 	return db.db.Close()
 }
 
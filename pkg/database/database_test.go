@@ -1,8 +1,19 @@
 package database
 
 import (
-	"github.com/stretchr/testify/assert"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"sync"
 	"testing"
+
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
 )
 
 // Ensure the test can open & close.
@@ -13,3 +24,203 @@ func TestDB(t *testing.T) {
 	err = db.Close()
 	assert.NoError(t, err)
 }
+
+func TestDB_Open(t *testing.T) {
+	t.Run("Given file database should set the pragmas of every connection", func(t *testing.T) {
+		db := NewDB(filepath.Join(t.TempDir(), "habheat.db"))
+		db.Logger = log.New(io.Discard, "", 0)
+		assert.NoError(t, db.Open())
+		defer db.Close()
+
+		ctx := context.Background()
+		// hold the connections so every one of them is a new connection
+		for i := 0; i < 3; i++ {
+			conn, err := db.db.Conn(ctx)
+			assert.NoError(t, err)
+			defer conn.Close()
+
+			var foreignKeys, busyTimeout int
+			var journalMode string
+			assert.NoError(t, conn.QueryRowContext(ctx, `PRAGMA foreign_keys`).Scan(&foreignKeys))
+			assert.NoError(t, conn.QueryRowContext(ctx, `PRAGMA busy_timeout`).Scan(&busyTimeout))
+			assert.NoError(t, conn.QueryRowContext(ctx, `PRAGMA journal_mode`).Scan(&journalMode))
+			assert.Equal(t, 1, foreignKeys)
+			assert.Equal(t, 5000, busyTimeout)
+			assert.Equal(t, "wal", journalMode)
+		}
+	})
+}
+
+func TestDB_Transactions(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB(filepath.Join(t.TempDir(), "habheat.db"))
+	db.Logger = log.New(io.Discard, "", 0)
+	assert.NoError(t, db.Open())
+	defer db.Close()
+
+	t.Run("Given read transactions should not take the write lock", func(t *testing.T) {
+		first, err := db.db.BeginTx(ctx, nil)
+		assert.NoError(t, err)
+		defer first.Rollback()
+		second, err := db.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		assert.NoError(t, err)
+		defer second.Rollback()
+
+		write, err := db.beginWrite(ctx)
+		assert.NoError(t, err)
+		assert.NoError(t, write.Rollback())
+	})
+
+	t.Run("Given concurrent updates should wait for the write lock", func(t *testing.T) {
+		service := NewHabitService(db)
+		habits := createDay(t, service, utils.CreateDate(2004, 1, 1), "Read", "Run", "Swim", "Walk")
+
+		var wg sync.WaitGroup
+		for _, habit := range habits {
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func(habit models.Habit, i int) {
+					defer wg.Done()
+					assert.NoError(t, habit.ChangeNote(fmt.Sprintf("note %d", i)))
+					assert.NoError(t, service.Update(ctx, &habit))
+				}(*habit, i)
+			}
+		}
+		wg.Wait()
+	})
+}
+
+func TestDB_FilePath(t *testing.T) {
+	tests := []struct {
+		name string
+		dsn  string
+		path string
+		ok   bool
+	}{
+		{"Given path should give it", "/data/habheat.db", "/data/habheat.db", true},
+		{"Given path with params should give the path", "habheat.db?_busy_timeout=1000", "habheat.db", true},
+		{"Given file URI should give its path", "file:/data/habheat.db?mode=rw", "/data/habheat.db", true},
+		{"Given relative file URI should give its path", "file:habheat.db", "habheat.db", true},
+		{"Given file URI with authority should give the unescaped path", "file:///data/my%20habits.db", "/data/my habits.db", true},
+		{"Given file URI with localhost should give its path", "file://localhost/data/habheat.db", "/data/habheat.db", true},
+		{"Given memory database should give no path", ":memory:", "", false},
+		{"Given shared memory database should give no path", "file::memory:?cache=shared", "", false},
+		{"Given memory mode should give no path", "file:habheat?mode=memory&cache=shared", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := NewDB(tt.dsn).filePath()
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.path, path)
+		})
+	}
+}
+
+const initVersion = 20240627105906
+
+// Opens an in memory database migrated up to the first version
+func setupInitDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	assert.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	goose.SetBaseFS(embedMigrations)
+	assert.NoError(t, goose.SetDialect("sqlite3"))
+	assert.NoError(t, goose.UpTo(db, "migration", initVersion))
+	return db
+}
+
+func TestTypedDatesMigration(t *testing.T) {
+	t.Run("Given old dates should normalize them", func(t *testing.T) {
+		db := setupInitDB(t)
+		_, err := db.Exec(`INSERT INTO habit (title, day, is_completed, updated_at) VALUES
+			('Read', '2023-11-01T00:00:00Z', 1, '2023-11-01 21:30:00.123456789+03:00'),
+			('Run', '2023-11-02T00:00:00Z', 0, NULL)`)
+		assert.NoError(t, err)
+
+		assert.NoError(t, goose.Up(db, "migration"))
+
+		rows, err := db.Query(`SELECT day, created_at, updated_at FROM habit ORDER BY id`)
+		assert.NoError(t, err)
+		defer rows.Close()
+		var got [][3]string
+		for rows.Next() {
+			var row [3]string
+			assert.NoError(t, rows.Scan(&row[0], &row[1], &row[2]))
+			got = append(got, row)
+		}
+		assert.Equal(t, [][3]string{
+			{"2023-11-01", "2023-11-01T18:30:00Z", "2023-11-01T18:30:00Z"},
+			{"2023-11-02", "2023-11-02T00:00:00Z", "2023-11-02T00:00:00Z"},
+		}, got)
+	})
+
+	t.Run("Given corrupt day should fail", func(t *testing.T) {
+		db := setupInitDB(t)
+		_, err := db.Exec(`INSERT INTO habit (title, day, is_completed, updated_at) VALUES ('Read', 'yesterday', 1, NULL)`)
+		assert.NoError(t, err)
+
+		err = goose.Up(db, "migration")
+
+		assert.ErrorContains(t, err, "NOT NULL constraint failed: habit_new.day")
+	})
+}
+
+func TestDB_Migrations(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Given file database should back up before migrating down and up", func(t *testing.T) {
+		dir := t.TempDir()
+		db := NewDB(filepath.Join(dir, "habheat.db"))
+		db.Logger = log.New(io.Discard, "", 0)
+		assert.NoError(t, db.Open())
+		defer db.Close()
+
+		current, latest, err := db.Version(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, latest, current)
+
+		result, err := db.Down(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, latest, result.Source.Version)
+
+		results, err := db.Up(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+
+		backups, _ := filepath.Glob(filepath.Join(dir, "habheat.db.*.bak"))
+		assert.Len(t, backups, 2)
+	})
+
+	t.Run("Given file URI database should back up next to the file", func(t *testing.T) {
+		dir := t.TempDir()
+		db := NewDB("file:" + filepath.Join(dir, "habheat.db") + "?mode=rwc")
+		db.Logger = log.New(io.Discard, "", 0)
+		assert.NoError(t, db.Open())
+		defer db.Close()
+
+		_, err := db.Down(ctx)
+		assert.NoError(t, err)
+
+		backups, _ := filepath.Glob(filepath.Join(dir, "habheat.db.*.bak"))
+		assert.Len(t, backups, 1)
+	})
+
+	t.Run("Given newer schema should refuse to open", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "habheat.db")
+		db := NewDB(path)
+		db.Logger = log.New(io.Discard, "", 0)
+		assert.NoError(t, db.Open())
+		_, err := db.db.Exec(`INSERT INTO goose_db_version (version_id, is_applied) VALUES (99990101000000, 1)`)
+		assert.NoError(t, err)
+		assert.NoError(t, db.Close())
+
+		db = NewDB(path)
+		err = db.Open()
+		defer db.Close()
+
+		assert.ErrorIs(t, err, ErrSchemaTooNew)
+	})
+}
@@ -3,7 +3,9 @@ package database
 import (
 	"context"
 	"database/sql"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/metagunner/habheat/pkg/app"
@@ -27,20 +29,22 @@ var _ models.HabitService = (*HabitServiceImpl)(nil)
 func (s *HabitServiceImpl) HeatMap(ctx context.Context, from time.Time, to time.Time) (map[time.Time]*models.HeatMap, int, error) {
 	const getHeatMapQuery = `
 		SELECT 
-			COUNT(*) AS total_number_of_habits,
+			SUM(CASE WHEN is_skipped = 0 THEN 1 ELSE 0 END) AS total_number_of_habits,
 			SUM(CASE WHEN is_completed = 1 THEN 1 ELSE 0 END) AS completed_habits,
+			SUM(CASE WHEN is_skipped = 1 THEN 1 ELSE 0 END) AS skipped_habits,
 		    strftime('%d', day) AS day,
 		    strftime('%m', day) AS month,
 		    strftime('%Y', day) AS year
 		FROM habit
 		WHERE day >= ? 
 			AND day <= ?
+			AND deleted_at IS NULL
 		GROUP BY day, month, year
 		ORDER BY year, month, day
 	`
 
-	fromQuery := from.UTC().Format(time.RFC3339)
-	toQuery := to.UTC().Format(time.RFC3339)
+	fromQuery := formatDay(from)
+	toQuery := formatDay(to)
 	rows, err := s.db.db.QueryContext(ctx, getHeatMapQuery, fromQuery, toQuery)
 	if err != nil {
 		return nil, 0, err
@@ -52,7 +56,7 @@ func (s *HabitServiceImpl) HeatMap(ctx context.Context, from time.Time, to time.
 		var day string
 		var month string
 		var year string
-		if err := rows.Scan(&h.TotalNumberOfHabits, &h.CompletedHabits, &day, &month, &year); err != nil {
+		if err := rows.Scan(&h.TotalNumberOfHabits, &h.CompletedHabits, &h.SkippedHabits, &day, &month, &year); err != nil {
 			return nil, 0, err
 		}
 		h.Day, _ = strconv.Atoi(day)
@@ -66,58 +70,216 @@ func (s *HabitServiceImpl) HeatMap(ctx context.Context, from time.Time, to time.
 		return nil, 0, err
 	}
 
+	// the habits on vacation days are not counted
+	vacations, err := listVacations(ctx, s.db.db, from, to)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, vacation := range vacations {
+		start := utils.ToDate(from)
+		if vacation.From.After(start) {
+			start = vacation.From
+		}
+		for day := start; !day.After(vacation.To) && !day.After(utils.ToDate(to)); day = day.AddDate(0, 0, 1) {
+			result[day] = &models.HeatMap{Vacation: true, Day: day.Day(), Month: int(day.Month()), Year: day.Year()}
+		}
+	}
+
 	return result, len(result), nil
 }
 
 func (s *HabitServiceImpl) GetAllByDay(ctx context.Context, day time.Time) (*models.Chain, error) {
 	const getHabitsQuery = `
-		SELECT 
-		    id,
-		    title,
-		    day,
-			is_completed,
-		    updated_at
+		SELECT ` + habitColumns + `
 		FROM habit
 		WHERE day = ?
-		ORDER BY id ASC
+			AND deleted_at IS NULL
+		ORDER BY position ASC, id ASC
 	`
 
-	tsQuery := day.UTC().Format(time.RFC3339)
-	rows, err := s.db.db.QueryContext(ctx, getHabitsQuery, tsQuery)
-	defer rows.Close()
+	day = utils.ToDate(day)
+	rows, err := s.db.db.QueryContext(ctx, getHabitsQuery, formatDay(day))
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	habits := make([]*models.Habit, 0)
 	for rows.Next() {
-		var h models.Habit
-		//(*NullTime)(chain.UpdatedAt)
-		var dayStr string
-		var updatedAtStr string
-		if err := rows.Scan(&h.Id, &h.Title, &dayStr, &h.IsCompleted, &updatedAtStr); err != nil {
+		habit, err := scanHabit(rows)
+		if err != nil {
 			return nil, err
 		}
-		h.Day, _ = time.Parse(time.RFC3339, dayStr)
-		h.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAtStr)
-		habits = append(habits, &h)
+		habits = append(habits, habit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
 	result := &models.Chain{Title: day.Format(time.DateOnly), Habits: habits}
 	return result, nil
 }
 
+func (s *HabitServiceImpl) GetById(ctx context.Context, id models.HabitId) (*models.Habit, error) {
+	const getHabitQuery = `
+		SELECT ` + habitColumns + `
+		FROM habit
+		WHERE id = ?
+			AND deleted_at IS NULL
+	`
+
+	rows, err := s.db.db.QueryContext(ctx, getHabitQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrHabitNotFound
+	}
+	return scanHabit(rows)
+}
+
+func (s *HabitServiceImpl) ListByRange(ctx context.Context, from time.Time, to time.Time) ([]*models.Habit, error) {
+	const listHabitsQuery = `
+		SELECT ` + habitColumns + `
+		FROM habit
+		WHERE day >= ?
+			AND day <= ?
+			AND deleted_at IS NULL
+		ORDER BY day ASC, position ASC, id ASC
+	`
+
+	rows, err := s.db.db.QueryContext(ctx, listHabitsQuery, formatDay(from), formatDay(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	habits := make([]*models.Habit, 0)
+	for rows.Next() {
+		habit, err := scanHabit(rows)
+		if err != nil {
+			return nil, err
+		}
+		habits = append(habits, habit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return habits, nil
+}
+
+// Columns of a habit row read by scanHabit
+const habitColumns = `
+		    id,
+		    title,
+		    day,
+			is_completed,
+			is_skipped,
+			position,
+			note,
+		    created_at,
+		    updated_at,
+		    archived_at,
+		    deleted_at`
+
+// Scans a habit row selected with the habitColumns
+func scanHabit(rows *sql.Rows) (*models.Habit, error) {
+	var h models.Habit
+	var dayStr string
+	var createdAtStr string
+	var updatedAtStr string
+	var archivedAtStr sql.NullString
+	var deletedAtStr sql.NullString
+	if err := rows.Scan(&h.Id, &h.Title, &dayStr, &h.IsCompleted, &h.IsSkipped, &h.Position, &h.Note, &createdAtStr, &updatedAtStr, &archivedAtStr, &deletedAtStr); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if h.Day, err = parseDay(h.Id, dayStr); err != nil {
+		return nil, err
+	}
+	if h.CreatedAt, err = parseTimestamp(h.Id, "created_at", createdAtStr); err != nil {
+		return nil, err
+	}
+	if h.UpdatedAt, err = parseTimestamp(h.Id, "updated_at", updatedAtStr); err != nil {
+		return nil, err
+	}
+	if h.ArchivedAt, err = parseNullTimestamp(h.Id, "archived_at", archivedAtStr); err != nil {
+		return nil, err
+	}
+	if h.DeletedAt, err = parseNullTimestamp(h.Id, "deleted_at", deletedAtStr); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
 func (s *HabitServiceImpl) Create(ctx context.Context, habit *models.Habit) error {
-	tx, err := s.db.db.BeginTx(ctx, nil)
+	tx, err := s.db.beginWrite(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createHabit(ctx, tx, habit); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *HabitServiceImpl) Save(ctx context.Context, habits []*models.Habit) error {
+	tx, err := s.db.beginWrite(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	const createHabitQuery = `INSERT INTO habit (title, day, is_completed, updated_at) VALUES (?, ?, ?, ?)`
+	for _, habit := range habits {
+		if habit.Id == 0 {
+			err = createHabit(ctx, tx, habit)
+		} else {
+			err = updateHabit(ctx, tx, habit)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// the archived habits don't appear on new days, the title is archived on a day
+// when it is archived on its last day before it
+const isArchivedQuery = `
+	SELECT archived_at IS NOT NULL
+	FROM habit
+	WHERE title = ? AND day <= ? AND deleted_at IS NULL
+	ORDER BY day DESC
+	LIMIT 1
+`
+
+func createHabit(ctx context.Context, tx *sql.Tx, habit *models.Habit) error {
+	var archived bool
+	err := tx.QueryRowContext(ctx, isArchivedQuery, habit.Title, formatDay(habit.Day)).Scan(&archived)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if archived {
+		return app.Errorf(app.ECONFLICT, "Habit %q is archived, unarchive it to track it again.", habit.Title)
+	}
+
+	// the new habit is the last one of the day
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(position) + 1, 0) FROM habit WHERE day = ?`, formatDay(habit.Day)).Scan(&habit.Position); err != nil {
+		return err
+	}
 
-	habitDay := habit.Day.Format(time.RFC3339)
-	result, err := tx.ExecContext(ctx, createHabitQuery, habit.Title, habitDay, habit.IsCompleted, habit.UpdatedAt)
+	const createHabitQuery = `INSERT INTO habit (title, day, is_completed, is_skipped, position, note, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := tx.ExecContext(ctx, createHabitQuery, habit.Title, formatDay(habit.Day), habit.IsCompleted, habit.IsSkipped, habit.Position, habit.Note, formatTimestamp(habit.CreatedAt), formatTimestamp(habit.UpdatedAt))
 	if err != nil {
 		return err
 	}
@@ -127,12 +289,154 @@ func (s *HabitServiceImpl) Create(ctx context.Context, habit *models.Habit) erro
 		return err
 	}
 	habit.Id = models.HabitId(id)
+	return nil
+}
 
-	return tx.Commit()
+func (s *HabitServiceImpl) Copy(ctx context.Context, ids []models.HabitId, days []time.Time) (int, error) {
+	if len(ids) == 0 || len(days) == 0 {
+		return 0, nil
+	}
+
+	tx, err := s.db.beginWrite(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	placeholders, args := idArgs(ids)
+	rows, err := tx.QueryContext(ctx, `
+		SELECT title
+		FROM habit
+		WHERE id IN (`+placeholders+`)
+			AND archived_at IS NULL
+			AND deleted_at IS NULL
+		ORDER BY day ASC, position ASC, id ASC
+	`, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	titles := make([]string, 0, len(ids))
+	for rows.Next() {
+		var title string
+		if err := rows.Scan(&title); err != nil {
+			return 0, err
+		}
+		if !slices.Contains(titles, title) {
+			titles = append(titles, title)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// the copy is the last habit of the day, it is skipped if the day already has
+	// the habit or the habit is archived on the day
+	insert, err := tx.PrepareContext(ctx, `
+		INSERT INTO habit (title, day, is_completed, is_skipped, position, created_at, updated_at)
+		SELECT ?, ?, 0, 0, (SELECT COALESCE(MAX(position) + 1, 0) FROM habit WHERE day = ?), ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM habit WHERE day = ? AND title = ? AND deleted_at IS NULL)
+			AND NOT COALESCE((`+isArchivedQuery+`), 0)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer insert.Close()
+
+	now := formatTimestamp(time.Now())
+	created := 0
+	for _, day := range days {
+		day := formatDay(day)
+		for _, title := range titles {
+			result, err := insert.ExecContext(ctx, title, day, day, now, now, day, title, title, day)
+			if err != nil {
+				return 0, err
+			}
+			n, err := result.RowsAffected()
+			if err != nil {
+				return 0, err
+			}
+			created += int(n)
+		}
+	}
+
+	return created, tx.Commit()
 }
 
 func (s *HabitServiceImpl) Delete(ctx context.Context, id models.HabitId) error {
-	tx, err := s.db.db.BeginTx(ctx, nil)
+	tx, err := s.db.beginWrite(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = checkHabitExists(ctx, tx, id); err != nil {
+		return err
+	}
+
+	deletedAt := formatTimestamp(time.Now())
+	if _, err := tx.ExecContext(ctx, `UPDATE habit SET deleted_at = ? WHERE id = ?`, deletedAt, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *HabitServiceImpl) Restore(ctx context.Context, id models.HabitId) error {
+	result, err := s.db.db.ExecContext(ctx, `UPDATE habit SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrHabitNotFound
+	}
+
+	return nil
+}
+
+func (s *HabitServiceImpl) Trash(ctx context.Context) ([]*models.Habit, error) {
+	const getTrashQuery = `
+		SELECT ` + habitColumns + `
+		FROM habit
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+	`
+
+	rows, err := s.db.db.QueryContext(ctx, getTrashQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	habits := make([]*models.Habit, 0)
+	for rows.Next() {
+		habit, err := scanHabit(rows)
+		if err != nil {
+			return nil, err
+		}
+		habits = append(habits, habit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return habits, nil
+}
+
+func (s *HabitServiceImpl) EmptyTrash(ctx context.Context) (int, error) {
+	result, err := s.db.db.ExecContext(ctx, `DELETE FROM habit WHERE deleted_at IS NOT NULL`)
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
+
+func (s *HabitServiceImpl) Archive(ctx context.Context, id models.HabitId, archived bool) error {
+	tx, err := s.db.beginWrite(ctx)
 	if err != nil {
 		return err
 	}
@@ -142,7 +446,29 @@ func (s *HabitServiceImpl) Delete(ctx context.Context, id models.HabitId) error
 		return err
 	}
 
-	if _, err := tx.ExecContext(ctx, `DELETE FROM habit WHERE id = ?`, id); err != nil {
+	// the habit is archived on its day and the days before, it is unarchived on
+	// every day. The habits in the trash are left as they are and the archive
+	// is not an edit of the habits, updated_at is kept.
+	query := `
+		UPDATE habit
+		SET archived_at = NULL
+		WHERE title = (SELECT title FROM habit WHERE id = ?)
+			AND archived_at IS NOT NULL
+			AND deleted_at IS NULL
+	`
+	args := []any{id}
+	if archived {
+		query = `
+			UPDATE habit
+			SET archived_at = ?
+			WHERE title = (SELECT title FROM habit WHERE id = ?)
+				AND day <= (SELECT day FROM habit WHERE id = ?)
+				AND archived_at IS NULL
+				AND deleted_at IS NULL
+		`
+		args = []any{formatTimestamp(time.Now()), id, id}
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 		return err
 	}
 
@@ -150,13 +476,20 @@ func (s *HabitServiceImpl) Delete(ctx context.Context, id models.HabitId) error
 }
 
 func (s *HabitServiceImpl) Update(ctx context.Context, habit *models.Habit) error {
-	tx, err := s.db.db.BeginTx(ctx, nil)
+	tx, err := s.db.beginWrite(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	if err = checkHabitExists(ctx, tx, habit.Id); err != nil {
+	if err := updateHabit(ctx, tx, habit); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func updateHabit(ctx context.Context, tx *sql.Tx, habit *models.Habit) error {
+	if err := checkHabitExists(ctx, tx, habit.Id); err != nil {
 		return err
 	}
 
@@ -164,22 +497,333 @@ func (s *HabitServiceImpl) Update(ctx context.Context, habit *models.Habit) erro
 		UPDATE habit
 		SET title = ?,
 			is_completed = ?,
+			is_skipped = ?,
+			note = ?,
 			updated_at = ?
 		WHERE id = ?
 	`,
 		habit.Title,
 		habit.IsCompleted,
-		habit.UpdatedAt,
+		habit.IsSkipped,
+		habit.Note,
+		formatTimestamp(habit.UpdatedAt),
 		habit.Id); err != nil {
 		return err
 	}
+	return nil
+}
+
+func (s *HabitServiceImpl) Move(ctx context.Context, id models.HabitId, offset int) error {
+	tx, err := s.db.beginWrite(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = checkHabitExists(ctx, tx, id); err != nil {
+		return err
+	}
+
+	var day string
+	var title string
+	if err := tx.QueryRowContext(ctx, `SELECT day, title FROM habit WHERE id = ?`, id).Scan(&day, &title); err != nil {
+		return err
+	}
+
+	type habitPosition struct {
+		id       models.HabitId
+		title    string
+		day      string
+		position int
+	}
+	queryPositions := func(query string, args ...any) ([]habitPosition, error) {
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		positions := make([]habitPosition, 0)
+		for rows.Next() {
+			var p habitPosition
+			if err := rows.Scan(&p.id, &p.title, &p.day, &p.position); err != nil {
+				return nil, err
+			}
+			positions = append(positions, p)
+		}
+		return positions, rows.Err()
+	}
+
+	habits, err := queryPositions(`
+		SELECT id, title, day, position
+		FROM habit
+		WHERE day = ?
+			AND deleted_at IS NULL
+		ORDER BY position ASC, id ASC
+	`, day)
+	if err != nil {
+		return err
+	}
+
+	from := slices.IndexFunc(habits, func(p habitPosition) bool { return p.id == id })
+	to := from + offset
+	if to < 0 || to >= len(habits) {
+		return nil
+	}
+	moved := habits[from]
+	habits = slices.Delete(habits, from, from+1)
+	habits = slices.Insert(habits, to, moved)
+	for i, habit := range habits {
+		if _, err := tx.ExecContext(ctx, `UPDATE habit SET position = ? WHERE id = ?`, i, habit.id); err != nil {
+			return err
+		}
+	}
+
+	// keep the new order on the following days the habits are tracked together
+	passed := make(map[string]bool)
+	for _, habit := range habits[min(from, to) : max(from, to)+1] {
+		if habit.id != id {
+			passed[habit.title] = true
+		}
+	}
+	following, err := queryPositions(`
+		SELECT id, title, day, position
+		FROM habit
+		WHERE day IN (SELECT day FROM habit WHERE day > ? AND title = ? AND deleted_at IS NULL)
+			AND deleted_at IS NULL
+		ORDER BY day ASC, position ASC, id ASC
+	`, day, title)
+	if err != nil {
+		return err
+	}
+	for len(following) > 0 {
+		end := slices.IndexFunc(following, func(p habitPosition) bool { return p.day != following[0].day })
+		if end == -1 {
+			end = len(following)
+		}
+		dayHabits := following[:end]
+		following = following[end:]
+
+		current := slices.IndexFunc(dayHabits, func(p habitPosition) bool { return p.title == title })
+		moved := dayHabits[current]
+		dayHabits = slices.Delete(slices.Clone(dayHabits), current, current+1)
+		target := current
+		if offset < 0 {
+			// before the first passed habit if it is before the moved one
+			if first := slices.IndexFunc(dayHabits, func(p habitPosition) bool { return passed[p.title] }); first != -1 && first < current {
+				target = first
+			}
+		} else {
+			// after the last passed habit if it is after the moved one
+			last := -1
+			for i, p := range dayHabits {
+				if passed[p.title] {
+					last = i
+				}
+			}
+			if last >= current {
+				target = last + 1
+			}
+		}
+		if target == current {
+			continue
+		}
+		dayHabits = slices.Insert(dayHabits, target, moved)
+		for i, habit := range dayHabits {
+			if _, err := tx.ExecContext(ctx, `UPDATE habit SET position = ? WHERE id = ?`, i, habit.id); err != nil {
+				return err
+			}
+		}
+	}
 
 	return tx.Commit()
 }
 
+func (s *HabitServiceImpl) DeleteMany(ctx context.Context, ids []models.HabitId) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	placeholders, args := idArgs(ids)
+	args = append([]any{formatTimestamp(time.Now())}, args...)
+	_, err := s.db.db.ExecContext(ctx, `UPDATE habit SET deleted_at = ? WHERE id IN (`+placeholders+`) AND deleted_at IS NULL`, args...)
+	return err
+}
+
+func (s *HabitServiceImpl) SetCompleted(ctx context.Context, ids []models.HabitId, completed bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// a completed habit is not skipped
+	placeholders, args := idArgs(ids)
+	args = append([]any{completed, completed, formatTimestamp(time.Now())}, args...)
+	_, err := s.db.db.ExecContext(ctx, `
+		UPDATE habit
+		SET is_completed = ?,
+			is_skipped = CASE WHEN ? THEN 0 ELSE is_skipped END,
+			updated_at = ?
+		WHERE id IN (`+placeholders+`)
+			AND deleted_at IS NULL
+	`, args...)
+	return err
+}
+
+func (s *HabitServiceImpl) SetSkipped(ctx context.Context, ids []models.HabitId, skipped bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// a skipped habit is not completed
+	placeholders, args := idArgs(ids)
+	args = append([]any{skipped, skipped, formatTimestamp(time.Now())}, args...)
+	_, err := s.db.db.ExecContext(ctx, `
+		UPDATE habit
+		SET is_skipped = ?,
+			is_completed = CASE WHEN ? THEN 0 ELSE is_completed END,
+			updated_at = ?
+		WHERE id IN (`+placeholders+`)
+			AND deleted_at IS NULL
+	`, args...)
+	return err
+}
+
+func (s *HabitServiceImpl) Rename(ctx context.Context, ids []models.HabitId, title models.HabitTitle) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.beginWrite(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	placeholders, args := idArgs(ids)
+	var count int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM habit WHERE id IN (`+placeholders+`) AND deleted_at IS NULL`, args...).Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrHabitNotFound
+	}
+
+	// two habits of the same day would have the same title
+	var day string
+	err = tx.QueryRowContext(ctx, `
+		SELECT day
+		FROM habit
+		WHERE id IN (`+placeholders+`)
+			AND deleted_at IS NULL
+		GROUP BY day
+		HAVING COUNT(*) > 1
+		ORDER BY day ASC
+		LIMIT 1
+	`, args...).Scan(&day)
+	if err == nil {
+		return app.Errorf(app.ECONFLICT, "The habits are tracked together on %s, they can't have the same title.", day)
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+
+	// the new title can't be tracked on a day of the renamed habits
+	err = tx.QueryRowContext(ctx, `
+		SELECT day
+		FROM habit
+		WHERE title = ?
+			AND id NOT IN (`+placeholders+`)
+			AND deleted_at IS NULL
+			AND day IN (SELECT day FROM habit WHERE id IN (`+placeholders+`) AND deleted_at IS NULL)
+		ORDER BY day ASC
+		LIMIT 1
+	`, append(append([]any{title}, args...), args...)...).Scan(&day)
+	if err == nil {
+		return app.Errorf(app.ECONFLICT, "Habit %q already exists on %s.", title, day)
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE habit
+		SET title = ?, updated_at = ?
+		WHERE id IN (`+placeholders+`)
+			AND title != ?
+			AND deleted_at IS NULL
+	`, append(append([]any{title, formatTimestamp(time.Now())}, args...), title)...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *HabitServiceImpl) Complete(ctx context.Context, title models.HabitTitle, days []time.Time) (int, error) {
+	tx, err := s.db.beginWrite(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	update, err := tx.PrepareContext(ctx, `
+		UPDATE habit
+		SET is_completed = 1, is_skipped = 0, updated_at = ?
+		WHERE day = ? AND title = ? AND is_completed = 0 AND deleted_at IS NULL
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer update.Close()
+
+	// the habit is the last one of the days that don't have it, it is not created
+	// on the days it is archived
+	insert, err := tx.PrepareContext(ctx, `
+		INSERT INTO habit (title, day, is_completed, is_skipped, position, created_at, updated_at)
+		SELECT ?, ?, 1, 0, (SELECT COALESCE(MAX(position) + 1, 0) FROM habit WHERE day = ?), ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM habit WHERE day = ? AND title = ? AND deleted_at IS NULL)
+			AND NOT COALESCE((`+isArchivedQuery+`), 0)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer insert.Close()
+
+	now := formatTimestamp(time.Now())
+	changed := 0
+	for _, day := range days {
+		day := formatDay(day)
+		result, err := update.ExecContext(ctx, now, day, title)
+		if err != nil {
+			return 0, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if n == 0 {
+			if result, err = insert.ExecContext(ctx, title, day, day, now, now, day, title, title, day); err != nil {
+				return 0, err
+			}
+			if n, err = result.RowsAffected(); err != nil {
+				return 0, err
+			}
+		}
+		changed += int(min(n, 1))
+	}
+
+	return changed, tx.Commit()
+}
+
+// Returns the placeholders and the arguments of the ids for an IN clause
+func idArgs(ids []models.HabitId) (string, []any) {
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	return "?" + strings.Repeat(", ?", len(ids)-1), args
+}
+
 func checkHabitExists(ctx context.Context, tx *sql.Tx, id models.HabitId) error {
 	var n int
-	if err := tx.QueryRowContext(ctx, `SELECT COUNT(1) FROM habit WHERE id = ?`, id).Scan(&n); err != nil {
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(1) FROM habit WHERE id = ? AND deleted_at IS NULL`, id).Scan(&n); err != nil {
 		return err
 	} else if n == 0 {
 		return ErrHabitNotFound
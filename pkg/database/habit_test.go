@@ -7,8 +7,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/metagunner/habheat/pkg/app"
 	"github.com/metagunner/habheat/pkg/models"
 	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -43,6 +45,43 @@ func TestHabitService_GetAllByDay(t *testing.T) {
 	assert.Equal(t, chain.Title, day.Format(time.DateOnly))
 }
 
+func TestHabitService_GetAllByDay_TimeZone(t *testing.T) {
+	service := NewHabitService(testDB)
+
+	// midnight in UTC+3 is the previous day in UTC
+	day := time.Date(TestYear, TestMonth, 2, 0, 0, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+
+	chain, err := service.GetAllByDay(context.Background(), day)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, chain.Habits)
+	assert.Equal(t, "2023-11-02", chain.Title)
+	for _, habit := range chain.Habits {
+		assert.Equal(t, utils.CreateDate(TestYear, TestMonth, 2), habit.Day)
+	}
+}
+
+func TestHabitService_GetAllByDay_CorruptRow(t *testing.T) {
+	service := NewHabitService(testDB)
+	ctx := context.Background()
+
+	// the check constraints keep the corrupt values out, skip them to write one
+	conn, err := testDB.db.Conn(ctx)
+	assert.NoError(t, err)
+	_, err = conn.ExecContext(ctx, `PRAGMA ignore_check_constraints = ON`)
+	assert.NoError(t, err)
+	_, err = conn.ExecContext(ctx, `INSERT INTO habit (title, day, is_completed, created_at, updated_at) VALUES ('Read', '1991-01-01', 0, 'yesterday', '1991-01-01T00:00:00Z')`)
+	assert.NoError(t, err)
+	_, err = conn.ExecContext(ctx, `PRAGMA ignore_check_constraints = OFF`)
+	assert.NoError(t, err)
+	assert.NoError(t, conn.Close())
+
+	_, err = service.GetAllByDay(ctx, utils.CreateDate(1991, 1, 1))
+
+	assert.Equal(t, app.EINTERNAL, app.ErrorCode(err))
+	assert.Contains(t, app.ErrorMessage(err), `invalid created_at "yesterday"`)
+}
+
 func TestHabitService_Create(t *testing.T) {
 	service := NewHabitService(testDB)
 
@@ -56,6 +95,53 @@ func TestHabitService_Create(t *testing.T) {
 	assert.NotZero(t, habit.Id)
 }
 
+func TestHabitService_GetById(t *testing.T) {
+	service := NewHabitService(testDB)
+	ctx := context.Background()
+
+	habitTitle, _ := models.CreateHabitTitle("Stretch")
+	habit, _ := models.CreateHabit(habitTitle, utils.CreateDate(2000, 1, 1), false)
+	assert.NoError(t, service.Create(ctx, habit))
+
+	t.Run("Given habit id should return the habit", func(t *testing.T) {
+		result, err := service.GetById(ctx, habit.Id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, habit.Title, result.Title)
+		assert.Equal(t, habit.Day, result.Day)
+	})
+	t.Run("Given deleted habit id should fail", func(t *testing.T) {
+		assert.NoError(t, service.Delete(ctx, habit.Id))
+
+		_, err := service.GetById(ctx, habit.Id)
+
+		assert.ErrorIs(t, err, ErrHabitNotFound)
+	})
+}
+
+func TestHabitService_ListByRange(t *testing.T) {
+	service := NewHabitService(testDB)
+	ctx := context.Background()
+
+	create := func(title string, day time.Time) *models.Habit {
+		habitTitle, _ := models.CreateHabitTitle(title)
+		habit, _ := models.CreateHabit(habitTitle, day, false)
+		assert.NoError(t, service.Create(ctx, habit))
+		return habit
+	}
+	create("Before", utils.CreateDate(2003, 2, 28))
+	second := create("Swim", utils.CreateDate(2003, 3, 2))
+	first := create("Read", utils.CreateDate(2003, 3, 1))
+	deleted := create("Deleted", utils.CreateDate(2003, 3, 1))
+	assert.NoError(t, service.Delete(ctx, deleted.Id))
+	create("After", utils.CreateDate(2003, 4, 1))
+
+	habits, err := service.ListByRange(ctx, utils.CreateDate(2003, 3, 1), utils.CreateDate(2003, 3, 31))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.HabitId{first.Id, second.Id}, lo.Map(habits, func(h *models.Habit, _ int) models.HabitId { return h.Id }))
+}
+
 func TestHabitService_Delete(t *testing.T) {
 	service := NewHabitService(testDB)
 	t.Run("Given not exist habit id should fail", func(t *testing.T) {
@@ -91,6 +177,41 @@ func TestHabitService_Update(t *testing.T) {
 	assert.Equal(t, !isCompleted, habit.IsCompleted)
 }
 
+func TestHabitService_Save(t *testing.T) {
+	service := NewHabitService(testDB)
+	ctx := context.Background()
+
+	day := utils.CreateDate(1988, 1, 1)
+	habits := createDay(t, service, day, "Read")
+	newHabit := func(title string) *models.Habit {
+		habitTitle, _ := models.CreateHabitTitle(title)
+		habit, _ := models.CreateHabit(habitTitle, day, false)
+		return habit
+	}
+
+	t.Run("Given new and changed habits should save them", func(t *testing.T) {
+		habits[0].ToggleCompletion()
+		run := newHabit("Run")
+
+		assert.NoError(t, service.Save(ctx, []*models.Habit{habits[0], run}))
+
+		assert.NotZero(t, run.Id)
+		result := getHabits(t, service, day)
+		assert.Equal(t, []string{"Read", "Run"}, getTitles(t, service, day))
+		assert.True(t, result[0].IsCompleted)
+	})
+
+	t.Run("Given failing habit should save nothing", func(t *testing.T) {
+		missing := newHabit("Missing")
+		missing.Id = 999999
+
+		err := service.Save(ctx, []*models.Habit{newHabit("Swim"), missing})
+
+		assert.Equal(t, app.ENOTFOUND, app.ErrorCode(err))
+		assert.Equal(t, []string{"Read", "Run"}, getTitles(t, service, day))
+	})
+}
+
 func TestHabitService_HeatMap(t *testing.T) {
 	service := NewHabitService(testDB)
 	ctx := context.Background()
@@ -131,3 +252,356 @@ func TestHabitService_HeatMap(t *testing.T) {
 		assert.Equal(t, expectedHeatMap.Year, actualHeatMap.Year)
 	}
 }
+
+func TestHabitService_Trash(t *testing.T) {
+	service := NewHabitService(testDB)
+	ctx := context.Background()
+
+	day := utils.CreateDate(1992, 1, 1)
+	title, _ := models.CreateHabitTitle("Meditate")
+	habit, _ := models.CreateHabit(title, day, true)
+	assert.NoError(t, service.Create(ctx, habit))
+
+	t.Run("Given deleted habit should move it to the trash", func(t *testing.T) {
+		assert.NoError(t, service.Delete(ctx, habit.Id))
+
+		chain, err := service.GetAllByDay(ctx, day)
+		assert.NoError(t, err)
+		assert.Empty(t, chain.Habits)
+
+		heatMap, _, err := service.HeatMap(ctx, day, day)
+		assert.NoError(t, err)
+		assert.Empty(t, heatMap)
+
+		trash, err := service.Trash(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, habit.Id, trash[0].Id)
+		assert.True(t, trash[0].IsDeleted())
+	})
+
+	t.Run("Given deleted habit should not delete it again", func(t *testing.T) {
+		assert.ErrorIs(t, service.Delete(ctx, habit.Id), ErrHabitNotFound)
+	})
+
+	t.Run("Given habit in the trash should restore it", func(t *testing.T) {
+		assert.NoError(t, service.Restore(ctx, habit.Id))
+
+		chain, err := service.GetAllByDay(ctx, day)
+		assert.NoError(t, err)
+		assert.Len(t, chain.Habits, 1)
+		assert.False(t, chain.Habits[0].IsDeleted())
+	})
+
+	t.Run("Given habit not in the trash should fail to restore", func(t *testing.T) {
+		assert.ErrorIs(t, service.Restore(ctx, habit.Id), ErrHabitNotFound)
+	})
+
+	t.Run("Given trash should empty it", func(t *testing.T) {
+		assert.NoError(t, service.Delete(ctx, habit.Id))
+
+		n, err := service.EmptyTrash(ctx)
+		assert.NoError(t, err)
+		assert.NotZero(t, n)
+
+		trash, err := service.Trash(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, trash)
+		assert.ErrorIs(t, service.Restore(ctx, habit.Id), ErrHabitNotFound)
+	})
+}
+
+func TestHabitService_Archive(t *testing.T) {
+	service := NewHabitService(testDB)
+	ctx := context.Background()
+
+	title, _ := models.CreateHabitTitle("Stretch")
+	first, _ := models.CreateHabit(title, utils.CreateDate(1993, 1, 1), true)
+	second, _ := models.CreateHabit(title, utils.CreateDate(1993, 1, 2), false)
+	later, _ := models.CreateHabit(title, utils.CreateDate(1993, 1, 3), false)
+	trashed, _ := models.CreateHabit(title, utils.CreateDate(1993, 1, 4), false)
+	first.UpdatedAt = first.UpdatedAt.Add(-time.Hour)
+	assert.NoError(t, service.Create(ctx, first))
+	assert.NoError(t, service.Create(ctx, second))
+	assert.NoError(t, service.Create(ctx, later))
+	assert.NoError(t, service.Create(ctx, trashed))
+	assert.NoError(t, service.Delete(ctx, trashed.Id))
+
+	t.Run("Given archived habit should archive it on its day and the days before", func(t *testing.T) {
+		assert.NoError(t, service.Archive(ctx, second.Id, true))
+
+		chain, err := service.GetAllByDay(ctx, first.Day)
+		assert.NoError(t, err)
+		assert.True(t, chain.Habits[0].IsArchived())
+		// the archive is not an edit of the habit
+		assert.Equal(t, first.UpdatedAt.Unix(), chain.Habits[0].UpdatedAt.Unix())
+		chain, err = service.GetAllByDay(ctx, later.Day)
+		assert.NoError(t, err)
+		assert.False(t, chain.Habits[0].IsArchived())
+
+		// the habits in the trash are not archived
+		trash, err := service.Trash(ctx)
+		assert.NoError(t, err)
+		inTrash, found := lo.Find(trash, func(h *models.Habit) bool { return h.Id == trashed.Id })
+		assert.True(t, found)
+		assert.False(t, inTrash.IsArchived())
+
+		// the completions of archived habits still count
+		heatMap, _, err := service.HeatMap(ctx, first.Day, first.Day)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, heatMap[first.Day].CompletedHabits)
+	})
+
+	t.Run("Given archived habit should not create it on new days", func(t *testing.T) {
+		assert.NoError(t, service.Archive(ctx, later.Id, true))
+		newDay := utils.CreateDate(1993, 1, 5)
+
+		habit, _ := models.CreateHabit(title, newDay, false)
+		assert.Equal(t, app.ECONFLICT, app.ErrorCode(service.Create(ctx, habit)))
+		assert.Equal(t, app.ECONFLICT, app.ErrorCode(service.Save(ctx, []*models.Habit{habit})))
+		created, err := service.Copy(ctx, []models.HabitId{first.Id}, []time.Time{newDay})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, created)
+		changed, err := service.Complete(ctx, title, []time.Time{newDay})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, changed)
+	})
+
+	t.Run("Given unarchived habit should unarchive it on every day", func(t *testing.T) {
+		assert.NoError(t, service.Archive(ctx, first.Id, false))
+
+		for _, day := range []time.Time{first.Day, second.Day, later.Day} {
+			chain, err := service.GetAllByDay(ctx, day)
+			assert.NoError(t, err)
+			assert.False(t, chain.Habits[0].IsArchived())
+		}
+		habit, _ := models.CreateHabit(title, utils.CreateDate(1993, 1, 5), false)
+		assert.NoError(t, service.Create(ctx, habit))
+	})
+}
+
+func TestHabitService_Move(t *testing.T) {
+	service := NewHabitService(testDB)
+	ctx := context.Background()
+
+	first := utils.CreateDate(1996, 1, 1)
+	second := utils.CreateDate(1996, 1, 2)
+	habits := createDay(t, service, first, "Read", "Run", "Swim")
+	createDay(t, service, second, "Read", "Swim", "Run")
+
+	t.Run("Given new habit should be the last one", func(t *testing.T) {
+		assert.Equal(t, 2, habits[2].Position)
+	})
+
+	t.Run("Given habit moved up should change the order", func(t *testing.T) {
+		assert.NoError(t, service.Move(ctx, habits[2].Id, -1))
+
+		assert.Equal(t, []string{"Read", "Swim", "Run"}, getTitles(t, service, first))
+		assert.Equal(t, []string{"Read", "Swim", "Run"}, getTitles(t, service, second))
+	})
+
+	t.Run("Given first habit moved up should keep the order", func(t *testing.T) {
+		assert.NoError(t, service.Move(ctx, habits[0].Id, -1))
+
+		assert.Equal(t, []string{"Read", "Swim", "Run"}, getTitles(t, service, first))
+	})
+
+	t.Run("Given habit moved should keep the order on the following days", func(t *testing.T) {
+		assert.NoError(t, service.Move(ctx, habits[0].Id, 2))
+
+		assert.Equal(t, []string{"Swim", "Run", "Read"}, getTitles(t, service, first))
+		// only the moved pair is reordered on the following days
+		assert.Equal(t, []string{"Swim", "Run", "Read"}, getTitles(t, service, second))
+	})
+}
+
+func TestHabitService_Copy(t *testing.T) {
+	service := NewHabitService(testDB)
+	ctx := context.Background()
+
+	first := utils.CreateDate(1997, 1, 1)
+	second := utils.CreateDate(1997, 1, 2)
+	third := utils.CreateDate(1997, 1, 3)
+	habits := createDay(t, service, first, "Read", "Run", "Swim", "Walk")
+	createDay(t, service, second, "Swim")
+	assert.NoError(t, service.SetCompleted(ctx, []models.HabitId{habits[0].Id, habits[1].Id}, true))
+	assert.NoError(t, service.Archive(ctx, habits[2].Id, true))
+	assert.NoError(t, service.Delete(ctx, habits[3].Id))
+	ids := []models.HabitId{habits[0].Id, habits[1].Id, habits[2].Id, habits[3].Id}
+
+	t.Run("Given habits copied to days should create them in their order", func(t *testing.T) {
+		created, err := service.Copy(ctx, ids, []time.Time{second, third})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, created)
+		assert.Equal(t, []string{"Swim", "Read", "Run"}, getTitles(t, service, second))
+		assert.Equal(t, []string{"Read", "Run"}, getTitles(t, service, third))
+		chain, err := service.GetAllByDay(ctx, third)
+		assert.NoError(t, err)
+		assert.False(t, chain.Habits[0].IsCompleted)
+	})
+
+	t.Run("Given habits copied again should not duplicate them", func(t *testing.T) {
+		created, err := service.Copy(ctx, ids, []time.Time{second, third})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, created)
+		assert.Equal(t, []string{"Read", "Run"}, getTitles(t, service, third))
+	})
+}
+
+func TestHabitService_Batch(t *testing.T) {
+	service := NewHabitService(testDB)
+	ctx := context.Background()
+
+	first := utils.CreateDate(1998, 1, 1)
+	second := utils.CreateDate(1998, 1, 2)
+	third := utils.CreateDate(1998, 1, 3)
+	habits := createDay(t, service, first, "Read", "Run", "Swim")
+	secondHabits := createDay(t, service, second, "Read", "Jog")
+
+	t.Run("Given habits marked completed should complete them", func(t *testing.T) {
+		habits[1].ToggleSkip()
+		assert.NoError(t, service.Update(ctx, habits[1]))
+
+		assert.NoError(t, service.SetCompleted(ctx, []models.HabitId{habits[0].Id, habits[1].Id}, true))
+
+		result := getHabits(t, service, first)
+		assert.True(t, result[0].IsCompleted)
+		assert.True(t, result[1].IsCompleted)
+		assert.False(t, result[1].IsSkipped)
+		assert.False(t, result[2].IsCompleted)
+	})
+
+	t.Run("Given habits marked skipped should skip them", func(t *testing.T) {
+		assert.NoError(t, service.SetSkipped(ctx, []models.HabitId{habits[1].Id, habits[2].Id}, true))
+
+		result := getHabits(t, service, first)
+		assert.False(t, result[0].IsSkipped)
+		assert.True(t, result[1].IsSkipped)
+		assert.False(t, result[1].IsCompleted)
+		assert.True(t, result[2].IsSkipped)
+
+		assert.NoError(t, service.SetSkipped(ctx, []models.HabitId{habits[1].Id}, false))
+		assert.False(t, getHabits(t, service, first)[1].IsSkipped)
+	})
+
+	t.Run("Given habits deleted should move them to the trash", func(t *testing.T) {
+		assert.NoError(t, service.DeleteMany(ctx, []models.HabitId{habits[1].Id, habits[2].Id}))
+
+		result := getHabits(t, service, first)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "Read", result[0].Title.String())
+	})
+
+	t.Run("Given habit renamed should rename only it", func(t *testing.T) {
+		title, _ := models.CreateHabitTitle("Reading")
+		assert.NoError(t, service.Rename(ctx, []models.HabitId{habits[0].Id}, title))
+
+		assert.Equal(t, "Reading", getHabits(t, service, first)[0].Title.String())
+		assert.Equal(t, "Read", getHabits(t, service, second)[0].Title.String())
+	})
+
+	t.Run("Given habits renamed to a title tracked on the same day should fail", func(t *testing.T) {
+		title, _ := models.CreateHabitTitle("Jog")
+		err := service.Rename(ctx, []models.HabitId{secondHabits[0].Id}, title)
+
+		assert.Equal(t, app.ECONFLICT, app.ErrorCode(err))
+		assert.Equal(t, "Read", getHabits(t, service, second)[0].Title.String())
+	})
+
+	t.Run("Given habit completed on days should create it where it is missing", func(t *testing.T) {
+		title, _ := models.CreateHabitTitle("Jog")
+		changed, err := service.Complete(ctx, title, []time.Time{first, second, third})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, changed)
+		for _, day := range []time.Time{first, second, third} {
+			habit, found := lo.Find(getHabits(t, service, day), func(x *models.Habit) bool { return x.Title.String() == "Jog" })
+			assert.True(t, found)
+			assert.True(t, habit.IsCompleted)
+		}
+
+		changed, err = service.Complete(ctx, title, []time.Time{first, second, third})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, changed)
+	})
+}
+
+func TestHabitService_Rename(t *testing.T) {
+	service := NewHabitService(testDB)
+	ctx := context.Background()
+
+	first := utils.CreateDate(1989, 1, 1)
+	second := utils.CreateDate(1989, 1, 2)
+	third := utils.CreateDate(1989, 1, 3)
+	firstHabits := createDay(t, service, first, "Read", "Run")
+	secondHabits := createDay(t, service, second, "Read", "Walk")
+	thirdHabits := createDay(t, service, third, "Read", "Swim")
+
+	t.Run("Given habits of the selected days should rename only them", func(t *testing.T) {
+		title, _ := models.CreateHabitTitle("Reading")
+		assert.NoError(t, service.Rename(ctx, []models.HabitId{firstHabits[0].Id, secondHabits[0].Id}, title))
+
+		assert.Equal(t, []string{"Reading", "Run"}, getTitles(t, service, first))
+		assert.Equal(t, []string{"Reading", "Walk"}, getTitles(t, service, second))
+		assert.Equal(t, []string{"Read", "Swim"}, getTitles(t, service, third))
+	})
+
+	t.Run("Given habits with different titles on different days should rename them to one title", func(t *testing.T) {
+		title, _ := models.CreateHabitTitle("Exercise")
+		assert.NoError(t, service.Rename(ctx, []models.HabitId{secondHabits[1].Id, thirdHabits[1].Id}, title))
+
+		assert.Equal(t, []string{"Reading", "Exercise"}, getTitles(t, service, second))
+		assert.Equal(t, []string{"Read", "Exercise"}, getTitles(t, service, third))
+	})
+
+	t.Run("Given habits of the same day should fail to merge them", func(t *testing.T) {
+		title, _ := models.CreateHabitTitle("Morning")
+		err := service.Rename(ctx, []models.HabitId{firstHabits[0].Id, firstHabits[1].Id}, title)
+
+		assert.Equal(t, app.ECONFLICT, app.ErrorCode(err))
+		assert.Equal(t, "The habits are tracked together on 1989-01-01, they can't have the same title.", app.ErrorMessage(err))
+		assert.Equal(t, []string{"Reading", "Run"}, getTitles(t, service, first))
+	})
+
+	t.Run("Given title tracked on a day of the habits should fail", func(t *testing.T) {
+		title, _ := models.CreateHabitTitle("Exercise")
+		err := service.Rename(ctx, []models.HabitId{firstHabits[0].Id, secondHabits[0].Id}, title)
+
+		assert.Equal(t, "Habit \"Exercise\" already exists on 1989-01-02.", app.ErrorMessage(err))
+		assert.Equal(t, []string{"Reading", "Run"}, getTitles(t, service, first))
+	})
+
+	t.Run("Given unchanged title should not update the habit", func(t *testing.T) {
+		habit := getHabits(t, service, first)[0]
+		habit.UpdatedAt = time.Date(1989, 1, 1, 20, 0, 0, 0, time.UTC)
+		assert.NoError(t, service.Update(ctx, habit))
+
+		title, _ := models.CreateHabitTitle("Reading")
+		assert.NoError(t, service.Rename(ctx, []models.HabitId{habit.Id}, title))
+
+		assert.Equal(t, habit.UpdatedAt, getHabits(t, service, first)[0].UpdatedAt)
+	})
+}
+
+// creates the habits of the day in their order
+func createDay(t *testing.T, service models.HabitService, day time.Time, titles ...string) []*models.Habit {
+	habits := make([]*models.Habit, 0, len(titles))
+	for _, title := range titles {
+		habitTitle, _ := models.CreateHabitTitle(title)
+		habit, _ := models.CreateHabit(habitTitle, day, false)
+		assert.NoError(t, service.Create(context.Background(), habit))
+		habits = append(habits, habit)
+	}
+	return habits
+}
+
+func getHabits(t *testing.T, service models.HabitService, day time.Time) []*models.Habit {
+	chain, err := service.GetAllByDay(context.Background(), day)
+	assert.NoError(t, err)
+	return chain.Habits
+}
+
+func getTitles(t *testing.T, service models.HabitService, day time.Time) []string {
+	return lo.Map(getHabits(t, service, day), func(habit *models.Habit, _ int) string { return habit.Title.String() })
+}
@@ -10,5 +10,5 @@ CREATE TABLE habit (
 CREATE INDEX idx_day ON habit (day);
 
 -- +goose Down
-DROP INDEX IF EXIST idx_day;
-DROP TABLE IF EXIST habit;
+DROP INDEX IF EXISTS idx_day;
+DROP TABLE IF EXISTS habit;
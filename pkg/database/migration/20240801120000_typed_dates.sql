@@ -0,0 +1,44 @@
+-- +goose Up
+-- Days are stored as YYYY-MM-DD and timestamps as RFC3339 in UTC.
+-- Habits without a valid day fail the NOT NULL constraint so the migration
+-- stops instead of dropping them.
+CREATE TABLE habit_new (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	title           TEXT NOT NULL,
+	day             TEXT NOT NULL CHECK (day = date(day)),
+	is_completed    INTEGER NOT NULL DEFAULT 0 CHECK (is_completed IN (0, 1)),
+	created_at      TEXT NOT NULL CHECK (created_at = strftime('%Y-%m-%dT%H:%M:%SZ', created_at)),
+	updated_at      TEXT NOT NULL CHECK (updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', updated_at))
+);
+
+INSERT INTO habit_new (id, title, day, is_completed, created_at, updated_at)
+SELECT
+	id,
+	title,
+	date(day),
+	CASE WHEN is_completed THEN 1 ELSE 0 END,
+	COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', updated_at), strftime('%Y-%m-%dT%H:%M:%SZ', day)),
+	COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', updated_at), strftime('%Y-%m-%dT%H:%M:%SZ', day))
+FROM habit;
+
+DROP INDEX idx_day;
+DROP TABLE habit;
+ALTER TABLE habit_new RENAME TO habit;
+CREATE INDEX idx_day ON habit (day);
+
+-- +goose Down
+CREATE TABLE habit_old (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	title           TEXT NOT NULL,
+	day             TEXT NOT NULL,
+	is_completed    INTEGER NOT NULL DEFAULT 0,
+	updated_at      TEXT
+);
+
+INSERT INTO habit_old (id, title, day, is_completed, updated_at)
+SELECT id, title, day || 'T00:00:00Z', is_completed, updated_at FROM habit;
+
+DROP INDEX idx_day;
+DROP TABLE habit;
+ALTER TABLE habit_old RENAME TO habit;
+CREATE INDEX idx_day ON habit (day);
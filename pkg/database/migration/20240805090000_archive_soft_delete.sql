@@ -0,0 +1,10 @@
+-- +goose Up
+-- Archived habits are not carried to new days but their completions still
+-- count. Deleted habits stay in the trash until it is emptied.
+ALTER TABLE habit ADD COLUMN archived_at TEXT CHECK (archived_at IS NULL OR archived_at = strftime('%Y-%m-%dT%H:%M:%SZ', archived_at));
+ALTER TABLE habit ADD COLUMN deleted_at TEXT CHECK (deleted_at IS NULL OR deleted_at = strftime('%Y-%m-%dT%H:%M:%SZ', deleted_at));
+
+-- +goose Down
+DELETE FROM habit WHERE deleted_at IS NOT NULL;
+ALTER TABLE habit DROP COLUMN deleted_at;
+ALTER TABLE habit DROP COLUMN archived_at;
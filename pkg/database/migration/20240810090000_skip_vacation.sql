@@ -0,0 +1,15 @@
+-- +goose Up
+-- Skipped habits and vacation days do not count as failures in the heat map.
+ALTER TABLE habit ADD COLUMN is_skipped INTEGER NOT NULL DEFAULT 0 CHECK (is_skipped IN (0, 1));
+
+CREATE TABLE vacation (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	start_day       TEXT NOT NULL CHECK (start_day = date(start_day)),
+	end_day         TEXT NOT NULL CHECK (end_day = date(end_day)),
+	created_at      TEXT NOT NULL CHECK (created_at = strftime('%Y-%m-%dT%H:%M:%SZ', created_at)),
+	CHECK (start_day <= end_day)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS vacation;
+ALTER TABLE habit DROP COLUMN is_skipped;
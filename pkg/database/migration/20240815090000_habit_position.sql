@@ -0,0 +1,13 @@
+-- +goose Up
+-- Position of the habit in its day, the habits are ordered by the creation order at first.
+ALTER TABLE habit ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+UPDATE habit SET position = (
+	SELECT COUNT(*) FROM habit AS previous WHERE previous.day = habit.day AND previous.id < habit.id
+);
+
+CREATE INDEX idx_day_position ON habit (day, position);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_day_position;
+ALTER TABLE habit DROP COLUMN position;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Free text note of the habit for the day.
+ALTER TABLE habit ADD COLUMN note TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+-- The search index triggers are created on open, they use the note column.
+DROP TRIGGER IF EXISTS habit_fts_insert;
+DROP TRIGGER IF EXISTS habit_fts_delete;
+DROP TRIGGER IF EXISTS habit_fts_update;
+ALTER TABLE habit DROP COLUMN note;
@@ -0,0 +1,198 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+)
+
+// The index is created and rebuilt once, then it is kept in sync by the
+// triggers. A build without FTS5 drops the triggers, the index is rebuilt when
+// they are created again so the changes made without them are indexed.
+const createSearchIndexQuery = `
+	CREATE VIRTUAL TABLE IF NOT EXISTS habit_fts USING fts5(title, note, content = 'habit', content_rowid = 'id');
+	CREATE TRIGGER IF NOT EXISTS habit_fts_insert AFTER INSERT ON habit BEGIN
+		INSERT INTO habit_fts (rowid, title, note) VALUES (new.id, new.title, new.note);
+	END;
+	CREATE TRIGGER IF NOT EXISTS habit_fts_delete AFTER DELETE ON habit BEGIN
+		INSERT INTO habit_fts (habit_fts, rowid, title, note) VALUES ('delete', old.id, old.title, old.note);
+	END;
+	CREATE TRIGGER IF NOT EXISTS habit_fts_update AFTER UPDATE OF title, note ON habit BEGIN
+		INSERT INTO habit_fts (habit_fts, rowid, title, note) VALUES ('delete', old.id, old.title, old.note);
+		INSERT INTO habit_fts (rowid, title, note) VALUES (new.id, new.title, new.note);
+	END;
+	INSERT INTO habit_fts (habit_fts) VALUES ('rebuild');
+`
+
+// the tables and the triggers of the index
+const countSearchIndexQuery = `
+	SELECT COUNT(*)
+	FROM sqlite_master
+	WHERE name IN ('habit_fts', 'habit_fts_insert', 'habit_fts_delete', 'habit_fts_update')
+`
+
+// Checks whether SQLite has FTS5, the notes are searched with LIKE without it.
+// The triggers of an index created by a build with FTS5 would fail every
+// change of the habits without it, they are dropped.
+func (db *DB) checkSearch(ctx context.Context) error {
+	if err := db.db.QueryRowContext(ctx, `SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&db.fullTextSearch); err != nil {
+		return err
+	}
+	if db.fullTextSearch {
+		return nil
+	}
+
+	var count int
+	if err := db.db.QueryRowContext(ctx, countSearchIndexQuery).Scan(&count); err != nil || count == 0 {
+		return err
+	}
+	_, err := db.db.ExecContext(ctx, `
+		DROP TRIGGER IF EXISTS habit_fts_insert;
+		DROP TRIGGER IF EXISTS habit_fts_delete;
+		DROP TRIGGER IF EXISTS habit_fts_update;
+	`)
+	return err
+}
+
+// Creates the full text search index of the titles and the notes when it or
+// one of its triggers is missing, the schema must be migrated first.
+func (db *DB) initSearch(ctx context.Context) error {
+	if !db.fullTextSearch {
+		return nil
+	}
+
+	var count int
+	if err := db.db.QueryRowContext(ctx, countSearchIndexQuery).Scan(&count); err != nil {
+		return err
+	}
+	if count == 4 {
+		return nil
+	}
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, createSearchIndexQuery); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *HabitServiceImpl) Search(ctx context.Context, query string, limit int) ([]*models.Habit, error) {
+	words := strings.Fields(query)
+	if len(words) == 0 {
+		return []*models.Habit{}, nil
+	}
+
+	// there are a few distinct titles, they are matched fuzzy
+	scores, err := s.scoreTitles(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// the words are matched as prefixes of the words in the titles and the notes
+	conditions := []string{}
+	args := []any{}
+	if s.db.fullTextSearch {
+		terms := make([]string, 0, len(words))
+		for _, word := range words {
+			terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"*`)
+		}
+		conditions = append(conditions, `id IN (SELECT rowid FROM habit_fts WHERE habit_fts MATCH ?)`)
+		args = append(args, strings.Join(terms, " "))
+	} else {
+		likes := make([]string, 0, len(words))
+		for _, word := range words {
+			likes = append(likes, `(title || ' ' || note) LIKE ? ESCAPE '\'`)
+			args = append(args, "%"+escapeLike(word)+"%")
+		}
+		conditions = append(conditions, "("+strings.Join(likes, " AND ")+")")
+	}
+
+	// the best matching titles are the first
+	orderBy := "day DESC, position ASC"
+	if len(scores) > 0 {
+		titles := make([]string, 0, len(scores))
+		for title := range scores {
+			titles = append(titles, title)
+		}
+		slices.Sort(titles)
+
+		placeholders := "?" + strings.Repeat(", ?", len(titles)-1)
+		conditions = append(conditions, "title IN ("+placeholders+")")
+		for _, title := range titles {
+			args = append(args, title)
+		}
+
+		var b strings.Builder
+		b.WriteString("CASE title")
+		for _, title := range titles {
+			fmt.Fprintf(&b, " WHEN ? THEN %d", scores[title])
+		}
+		b.WriteString(" ELSE 0 END DESC, ")
+		orderBy = b.String() + orderBy
+		for _, title := range titles {
+			args = append(args, title)
+		}
+	}
+	args = append(args, limit)
+
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT `+habitColumns+`
+		FROM habit
+		WHERE deleted_at IS NULL
+			AND (`+strings.Join(conditions, " OR ")+`)
+		ORDER BY `+orderBy+`
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	habits := make([]*models.Habit, 0)
+	for rows.Next() {
+		habit, err := scanHabit(rows)
+		if err != nil {
+			return nil, err
+		}
+		habits = append(habits, habit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return habits, nil
+}
+
+// Returns the score of every title that matches the query
+func (s *HabitServiceImpl) scoreTitles(ctx context.Context, query string) (map[string]int, error) {
+	rows, err := s.db.db.QueryContext(ctx, `SELECT DISTINCT title FROM habit WHERE deleted_at IS NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scores := make(map[string]int)
+	for rows.Next() {
+		var title string
+		if err := rows.Scan(&title); err != nil {
+			return nil, err
+		}
+		if score, ok := utils.FuzzyScore(query, title); ok {
+			scores[title] = score
+		}
+	}
+	return scores, rows.Err()
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,6 @@
+//go:build sqlite_fts5
+
+package database
+
+// the release builds have FTS5, see .goreleaser.yml
+const wantFullTextSearch = true
@@ -0,0 +1,6 @@
+//go:build !sqlite_fts5
+
+package database
+
+// the notes are searched with LIKE without FTS5
+const wantFullTextSearch = false
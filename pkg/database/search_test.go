@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"io"
+	"log"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDB_FullTextSearch(t *testing.T) {
+	// run the tests with -tags sqlite_fts5 to test the full text search, e.g. make test-fts5
+	assert.Equal(t, wantFullTextSearch, testDB.fullTextSearch)
+}
+
+func TestDB_SearchIndex(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "habheat.db")
+	open := func(skipMigrations bool) *DB {
+		db := NewDB(path)
+		db.Logger = log.New(io.Discard, "", 0)
+		db.SkipMigrations = skipMigrations
+		assert.NoError(t, db.Open())
+		return db
+	}
+
+	db := open(false)
+	// a build without FTS5 drops the triggers
+	_, err := db.db.ExecContext(ctx, `DROP TRIGGER IF EXISTS habit_fts_insert`)
+	assert.NoError(t, err)
+	title, _ := models.CreateHabitTitle("Juggle")
+	habit, _ := models.CreateHabit(title, utils.CreateDate(1999, 2, 1), false)
+	assert.NoError(t, habit.ChangeNote("Xylophone practice"))
+	assert.NoError(t, NewHabitService(db).Create(ctx, habit))
+	assert.NoError(t, db.Close())
+
+	t.Run("Given database without migrations should index the changes made without the triggers", func(t *testing.T) {
+		db := open(true)
+		defer db.Close()
+
+		habits, err := NewHabitService(db).Search(ctx, "xylophone", 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, wantFullTextSearch, db.fullTextSearch)
+		assert.Len(t, habits, 1)
+	})
+}
+
+func TestHabitService_Search(t *testing.T) {
+	service := NewHabitService(testDB)
+	ctx := context.Background()
+
+	create := func(title string, day time.Time, note string) *models.Habit {
+		habitTitle, _ := models.CreateHabitTitle(title)
+		habit, _ := models.CreateHabit(habitTitle, day, false)
+		assert.NoError(t, habit.ChangeNote(note))
+		assert.NoError(t, service.Create(ctx, habit))
+		return habit
+	}
+
+	first := create("Dentist floss", utils.CreateDate(1999, 1, 1), "")
+	second := create("Dentist floss", utils.CreateDate(1999, 1, 2), "")
+	noted := create("Walk", utils.CreateDate(1999, 1, 3), "Zygomatic stretch after walking")
+	deleted := create("Dentist floss", utils.CreateDate(1999, 1, 4), "")
+	assert.NoError(t, service.Delete(ctx, deleted.Id))
+
+	getIds := func(habits []*models.Habit) []models.HabitId {
+		ids := make([]models.HabitId, 0, len(habits))
+		for _, habit := range habits {
+			ids = append(ids, habit.Id)
+		}
+		return ids
+	}
+
+	t.Run("Given fuzzy title should give the days latest first", func(t *testing.T) {
+		habits, err := service.Search(ctx, "dntst flss", 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []models.HabitId{second.Id, first.Id}, getIds(habits))
+	})
+
+	t.Run("Given word of the note should give the day", func(t *testing.T) {
+		habits, err := service.Search(ctx, "zygomat", 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []models.HabitId{noted.Id}, getIds(habits))
+		assert.Equal(t, "Zygomatic stretch after walking", habits[0].Note)
+	})
+
+	t.Run("Given limit should give the best matches", func(t *testing.T) {
+		habits, err := service.Search(ctx, "dentist floss", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []models.HabitId{second.Id}, getIds(habits))
+	})
+
+	t.Run("Given changed note should find the new note only", func(t *testing.T) {
+		assert.NoError(t, noted.ChangeNote("Quadriceps stretch"))
+		assert.NoError(t, service.Update(ctx, noted))
+
+		habits, err := service.Search(ctx, "quadricep", 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.HabitId{noted.Id}, getIds(habits))
+
+		habits, err = service.Search(ctx, "zygomat", 10)
+		assert.NoError(t, err)
+		assert.Empty(t, habits)
+	})
+
+	t.Run("Given empty query should give nothing", func(t *testing.T) {
+		habits, err := service.Search(ctx, " ", 10)
+
+		assert.NoError(t, err)
+		assert.Empty(t, habits)
+	})
+}
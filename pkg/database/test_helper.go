@@ -37,7 +37,7 @@ func SeedTestData(ctx context.Context, db *DB, year int, month time.Month) {
 		defer tx.Rollback()
 
 		var b strings.Builder
-		b.WriteString("INSERT INTO habit (title, day, is_completed, updated_at) VALUES")
+		b.WriteString("INSERT INTO habit (title, day, is_completed, created_at, updated_at) VALUES")
 
 		maxHabitsPerDay := 6
 		var values []interface{}
@@ -60,14 +60,14 @@ func SeedTestData(ctx context.Context, db *DB, year int, month time.Month) {
 						b.WriteString(", ")
 					}
 
-					b.WriteString("(?, ?, ?, ?)")
-					values = append(values, habit.Title, habit.Day.Format(time.RFC3339), habit.IsCompleted, habit.UpdatedAt.Format(time.RFC3339))
+					b.WriteString("(?, ?, ?, ?, ?)")
+					values = append(values, habit.Title, formatDay(habit.Day), habit.IsCompleted, formatTimestamp(habit.CreatedAt), formatTimestamp(habit.UpdatedAt))
 				}
 			}
 		}
 
 		bulkInsert := b.String() + ";"
-		result, err := db.db.ExecContext(ctx, bulkInsert, values...)
+		result, err := tx.ExecContext(ctx, bulkInsert, values...)
 		if err != nil {
 			panic(err)
 		}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/app"
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+)
+
+const (
+	// Format of the habit days
+	dayFormat = time.DateOnly
+	// Format of the created and updated timestamps, they are always in UTC
+	timestampFormat = time.RFC3339
+)
+
+func formatDay(day time.Time) string {
+	return utils.ToDate(day).Format(dayFormat)
+}
+
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(timestampFormat)
+}
+
+// Parses a day read from the database, a corrupt value is reported instead of
+// being read as the zero time
+func parseDay(id models.HabitId, value string) (time.Time, error) {
+	day, err := time.Parse(dayFormat, value)
+	if err != nil {
+		return time.Time{}, app.Errorf(app.EINTERNAL, "Habit %v has an invalid day %q.", id, value)
+	}
+	return day, nil
+}
+
+func parseTimestamp(id models.HabitId, column string, value string) (time.Time, error) {
+	t, err := time.Parse(timestampFormat, value)
+	if err != nil {
+		return time.Time{}, app.Errorf(app.EINTERNAL, "Habit %v has an invalid %s %q.", id, column, value)
+	}
+	return t, nil
+}
+
+func parseNullTimestamp(id models.HabitId, column string, value sql.NullString) (*time.Time, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+	t, err := parseTimestamp(id, column, value.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
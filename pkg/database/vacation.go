@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/app"
+	"github.com/metagunner/habheat/pkg/models"
+)
+
+var ErrVacationNotFound = app.Errorf(app.ENOTFOUND, "Vacation not found.")
+
+type VacationServiceImpl struct {
+	db *DB
+}
+
+func NewVacationService(db *DB) models.VacationService {
+	return &VacationServiceImpl{db: db}
+}
+
+// Compile-time check to ensure VacationServiceImpl implements VacationService
+var _ models.VacationService = (*VacationServiceImpl)(nil)
+
+func (s *VacationServiceImpl) List(ctx context.Context) ([]*models.Vacation, error) {
+	return listVacations(ctx, s.db.db, time.Time{}, time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC))
+}
+
+func (s *VacationServiceImpl) Create(ctx context.Context, vacation *models.Vacation) error {
+	result, err := s.db.db.ExecContext(ctx, `INSERT INTO vacation (start_day, end_day, created_at) VALUES (?, ?, ?)`,
+		formatDay(vacation.From), formatDay(vacation.To), formatTimestamp(vacation.CreatedAt))
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	vacation.Id = models.VacationId(id)
+
+	return nil
+}
+
+func (s *VacationServiceImpl) Delete(ctx context.Context, id models.VacationId) error {
+	result, err := s.db.db.ExecContext(ctx, `DELETE FROM vacation WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrVacationNotFound
+	}
+
+	return nil
+}
+
+// Returns the vacations that overlap with the days from and to
+func listVacations(ctx context.Context, db *sql.DB, from time.Time, to time.Time) ([]*models.Vacation, error) {
+	const getVacationsQuery = `
+		SELECT id, start_day, end_day, created_at
+		FROM vacation
+		WHERE end_day >= ?
+			AND start_day <= ?
+		ORDER BY start_day DESC, id DESC
+	`
+
+	rows, err := db.QueryContext(ctx, getVacationsQuery, formatDay(from), formatDay(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	vacations := make([]*models.Vacation, 0)
+	for rows.Next() {
+		var v models.Vacation
+		var fromStr, toStr, createdAtStr string
+		if err := rows.Scan(&v.Id, &fromStr, &toStr, &createdAtStr); err != nil {
+			return nil, err
+		}
+		if v.From, err = time.Parse(dayFormat, fromStr); err != nil {
+			return nil, app.Errorf(app.EINTERNAL, "Vacation %d has an invalid start day %q.", v.Id, fromStr)
+		}
+		if v.To, err = time.Parse(dayFormat, toStr); err != nil {
+			return nil, app.Errorf(app.EINTERNAL, "Vacation %d has an invalid end day %q.", v.Id, toStr)
+		}
+		if v.CreatedAt, err = time.Parse(timestampFormat, createdAtStr); err != nil {
+			return nil, app.Errorf(app.EINTERNAL, "Vacation %d has an invalid created_at %q.", v.Id, createdAtStr)
+		}
+		vacations = append(vacations, &v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return vacations, nil
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVacationService(t *testing.T) {
+	service := NewVacationService(testDB)
+	ctx := context.Background()
+
+	vacation, _ := models.CreateVacation(utils.CreateDate(1995, 7, 1), utils.CreateDate(1995, 7, 14))
+
+	t.Run("Given vacation should create it", func(t *testing.T) {
+		assert.NoError(t, service.Create(ctx, vacation))
+		assert.NotZero(t, vacation.Id)
+
+		vacations, err := service.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, vacation.Id, vacations[0].Id)
+		assert.Equal(t, vacation.From, vacations[0].From)
+		assert.Equal(t, vacation.To, vacations[0].To)
+	})
+
+	t.Run("Given vacation id should delete it", func(t *testing.T) {
+		assert.NoError(t, service.Delete(ctx, vacation.Id))
+		assert.ErrorIs(t, service.Delete(ctx, vacation.Id), ErrVacationNotFound)
+	})
+}
+
+func TestHabitService_HeatMap_SkippedAndVacation(t *testing.T) {
+	habitService := NewHabitService(testDB)
+	vacationService := NewVacationService(testDB)
+	ctx := context.Background()
+
+	title, _ := models.CreateHabitTitle("Swim")
+	skipped, _ := models.CreateHabit(title, utils.CreateDate(1994, 3, 1), false)
+	skipped.ToggleSkip()
+	completed, _ := models.CreateHabit(title, utils.CreateDate(1994, 3, 1), true)
+	onVacation, _ := models.CreateHabit(title, utils.CreateDate(1994, 3, 2), false)
+	for _, habit := range []*models.Habit{skipped, completed, onVacation} {
+		assert.NoError(t, habitService.Create(ctx, habit))
+	}
+	vacation, _ := models.CreateVacation(utils.CreateDate(1994, 3, 2), utils.CreateDate(1994, 3, 10))
+	assert.NoError(t, vacationService.Create(ctx, vacation))
+
+	heatMap, _, err := habitService.HeatMap(ctx, utils.CreateDate(1994, 3, 1), utils.CreateDate(1994, 3, 3))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[time.Time]*models.HeatMap{
+		utils.CreateDate(1994, 3, 1): {TotalNumberOfHabits: 1, CompletedHabits: 1, SkippedHabits: 1, Day: 1, Month: 3, Year: 1994},
+		utils.CreateDate(1994, 3, 2): {Vacation: true, Day: 2, Month: 3, Year: 1994},
+		utils.CreateDate(1994, 3, 3): {Vacation: true, Day: 3, Month: 3, Year: 1994},
+	}, heatMap)
+}
@@ -0,0 +1,229 @@
+package gui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/metagunner/habheat/pkg/config"
+)
+
+// Action is a keybinding of a view together with the handler it runs. The
+// keybindings and the command palette are both set up from the actions.
+type Action struct {
+	// Name of the gocui view, empty for the global actions
+	View string
+	// Title of the view the action belongs to
+	ViewTitle string
+	config.Binding
+	Handler func() error
+}
+
+// the name of the action that is unique among every view
+func (a Action) name() string {
+	return a.View + "." + a.Binding.Action
+}
+
+// handlers of the actions of a view by the action names of the keybindings
+type actionHandlers map[string]func() error
+
+// merges the handlers, the later handlers replace the earlier ones with the same name
+func mergeActionHandlers(handlers ...actionHandlers) actionHandlers {
+	result := actionHandlers{}
+	for _, h := range handlers {
+		for action, handler := range h {
+			result[action] = handler
+		}
+	}
+	return result
+}
+
+// Returns the handlers of every view by the view name
+func (gui *Gui) getActionHandlers() map[string]actionHandlers {
+	return map[string]actionHandlers{
+		"": {
+			"quit":           quit,
+			"openHelp":       gui.HelpPanel.OpenHelpPanel,
+			"openTrash":      gui.TrashPanel.OpenTrashPanel,
+			"search":         gui.SearchPanel.OpenSearch,
+			"commandPalette": gui.CommandPalette.OpenCommandPalette,
+			"focusYears":     func() error { return gui.nextWindow("years") },
+			"focusHeatmap":   func() error { return gui.nextWindow("heatmap") },
+			"nextWindow":     gui.focusNextWindow,
+		},
+		"years": mergeActionHandlers(gui.YearsSelectList.actionHandlers(), actionHandlers{
+			"select": gui.selectYear,
+		}),
+		"heatmap": {
+			"right":         func() error { return gui.moveCursor(0, 1) },
+			"left":          func() error { return gui.moveCursor(0, -1) },
+			"up":            func() error { return gui.moveCursor(-1, 0) },
+			"down":          func() error { return gui.moveCursor(1, 0) },
+			"nextWeek":      func() error { return gui.moveWeek(1) },
+			"prevWeek":      func() error { return gui.moveWeek(-1) },
+			"rowStart":      func() error { return gui.moveToRowEdge(false) },
+			"rowEnd":        func() error { return gui.moveToRowEdge(true) },
+			"firstDay":      func() error { return gui.moveToEdgeDay(false) },
+			"lastDay":       func() error { return gui.moveToEdgeDay(true) },
+			"select":        gui.ChainPanel.OpenChainPanel,
+			"selectRange":   gui.toggleRangeSelect,
+			"markHabitDone": gui.markHabitDone,
+			"renameHabit":   gui.renameHabit,
+			"close":         gui.cancelRangeSelect,
+		},
+		"chainpanel": gui.ChainPanel.actionHandlers(),
+		"habitpanel": gui.HabitsPanel.actionHandlers(),
+		"search":     gui.SearchPanel.actionHandlers(),
+		"trash":      gui.TrashPanel.actionHandlers(),
+		"help":       gui.HelpPanel.actionHandlers(),
+		"palette":    gui.CommandPalette.actionHandlers(),
+	}
+}
+
+// Returns the actions of every keybinding in the order of the keybindings
+func (gui *Gui) getActions() ([]Action, error) {
+	handlers := gui.getActionHandlers()
+	actions := []Action{}
+	for _, group := range gui.Config.Keybinding.GetViewBindings() {
+		for _, binding := range group.Bindings {
+			handler, ok := handlers[group.View][binding.Action]
+			if !ok {
+				return nil, fmt.Errorf("no handler for the %s action of the %s view", binding.Action, group.Title)
+			}
+			actions = append(actions, Action{View: group.View, ViewTitle: group.Title, Binding: binding, Handler: handler})
+		}
+	}
+	return actions, nil
+}
+
+// a key sequence of an action, e.g. "gg"
+type keybinding struct {
+	keys   []config.Key
+	action Action
+}
+
+// Binds every key press of the actions to the dispatcher that finds the action
+// of the typed sequence. A key press is bound once per view even when it is
+// used by more than one sequence.
+func (gui *Gui) setKeybindings() error {
+	actions, err := gui.getActions()
+	if err != nil {
+		return err
+	}
+	gui.actions = actions
+	gui.keybindings = map[string][]keybinding{}
+	gui.pendingKeys = nil
+
+	bound := map[string]map[config.Key]bool{}
+	bind := func(view string, key config.Key) error {
+		if bound[view] == nil {
+			bound[view] = map[config.Key]bool{}
+		}
+		if bound[view][key] {
+			return nil
+		}
+		bound[view][key] = true
+		return gui.g.SetKeybinding(view, key.Key, key.Mod, gui.wrappedHandler(func() error { return gui.handleKey(key) }))
+	}
+	for _, action := range actions {
+		sequences, err := config.ParseKeys(action.Key)
+		if err != nil {
+			return err
+		}
+		for _, keys := range sequences {
+			gui.keybindings[action.View] = append(gui.keybindings[action.View], keybinding{keys: keys, action: action})
+			for _, key := range keys {
+				if err := bind(action.View, key); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	// the digits of the counts
+	for _, view := range countViews {
+		for r := '0'; r <= '9'; r++ {
+			if err := bind(view, config.Key{Key: r}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Runs the action of the typed key sequence. The keys are kept pending while
+// they start a longer sequence, a key that doesn't continue the pending ones
+// starts a new sequence. The digits typed before an action are its count, a
+// digit of a global action runs it when no key follows it, see typeCount.
+func (gui *Gui) handleKey(key config.Key) error {
+	viewName := ""
+	if currentView := gui.g.CurrentView(); currentView != nil {
+		viewName = currentView.Name()
+	}
+	if viewName != gui.pendingView {
+		gui.pendingKeys = nil
+		gui.count = 0
+	}
+	gui.stopCountAction()
+	if gui.typeCount(viewName, key) {
+		if gui.countAction != nil {
+			gui.startCountAction()
+		}
+		return nil
+	}
+
+	keys := append(slices.Clone(gui.pendingKeys), key)
+	binding, isPrefix := gui.findKeybinding(viewName, keys)
+	if binding == nil && !isPrefix && len(gui.pendingKeys) > 0 {
+		keys = []config.Key{key}
+		binding, isPrefix = gui.findKeybinding(viewName, keys)
+	}
+
+	gui.pendingKeys = nil
+	if binding != nil {
+		// the count is used by the motions, other actions ignore it
+		err := binding.action.Handler()
+		gui.count = 0
+		return err
+	}
+	if isPrefix {
+		gui.pendingKeys = keys
+		gui.pendingView = viewName
+	} else {
+		gui.count = 0
+	}
+	return nil
+}
+
+// Returns the keybinding of the keys in the view or the global one, and whether
+// the keys start a longer sequence
+func (gui *Gui) findKeybinding(viewName string, keys []config.Key) (*keybinding, bool) {
+	isPrefix := false
+	for _, view := range []string{viewName, ""} {
+		for i, binding := range gui.keybindings[view] {
+			if slices.Equal(binding.keys, keys) {
+				return &gui.keybindings[view][i], false
+			}
+			if len(binding.keys) > len(keys) && slices.Equal(binding.keys[:len(keys)], keys) {
+				isPrefix = true
+			}
+		}
+	}
+	return nil, isPrefix
+}
+
+// Returns the keybindings that continue the pending keys
+func (gui *Gui) getPendingKeybindings() []keybinding {
+	if len(gui.pendingKeys) == 0 {
+		return nil
+	}
+
+	result := []keybinding{}
+	for _, view := range []string{gui.pendingView, ""} {
+		for _, binding := range gui.keybindings[view] {
+			if len(binding.keys) > len(gui.pendingKeys) && slices.Equal(binding.keys[:len(gui.pendingKeys)], gui.pendingKeys) {
+				result = append(result, binding)
+			}
+		}
+	}
+	return result
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jesseduffield/gocui"
-	"github.com/metagunner/habheat/pkg/config"
+	"github.com/metagunner/habheat/pkg/app"
 	"github.com/metagunner/habheat/pkg/models"
 	"github.com/metagunner/habheat/pkg/utils"
 	"github.com/samber/lo"
@@ -29,7 +30,7 @@ func NewChainPanelContext(v *gocui.View, gui *Gui, habitService models.HabitServ
 	viewModel := &ChainPanelViewModel{}
 	getDisplayStrings := func() []SelectItem {
 		date := viewModel.selectedDay
-		date = utils.CreateDate(date.Year(), date.Month(), date.Day())
+		date = utils.ToDate(date)
 		habitChain, err := habitService.GetAllByDay(context.Background(), date)
 		if err != nil {
 			return []SelectItem{}
@@ -41,14 +42,22 @@ func NewChainPanelContext(v *gocui.View, gui *Gui, habitService models.HabitServ
 				status := " "
 				if habit.IsCompleted {
 					status = "X"
+				} else if habit.IsSkipped {
+					status = "-"
 				}
-				result = append(result, SelectItem{id: int(habit.Id), option: fmt.Sprintf("%d. [%s] %s", i+1, status, habit.Title)})
+				option := fmt.Sprintf("%d. [%s] %s", i+1, status, habit.Title)
+				if habit.IsArchived() {
+					option += " (archived)"
+				}
+				if note, _, _ := strings.Cut(habit.Note, "\n"); note != "" {
+					option += "  " + note
+				}
+				result = append(result, SelectItem{id: int(habit.Id), option: option})
 			}
 		}
 		return result
 	}
 	viewModel.list = NewSelectList(gui, v, getDisplayStrings)
-	viewModel.list.SetEmptyMessage("No habits for this day. Create one by presing the " + gui.Config.Keybinding.Heatmap.CreateHabit)
 
 	chainPanelContext := &ChainPanelContext{
 		viewModel:    viewModel,
@@ -57,16 +66,28 @@ func NewChainPanelContext(v *gocui.View, gui *Gui, habitService models.HabitServ
 		gui:          gui,
 	}
 
-	heatmapKeys := gui.Config.Keybinding.Heatmap
-	gui.g.SetKeybinding(v.Name(), config.GetKey(heatmapKeys.ToggleHabit), gocui.ModNone, gui.wrappedHandler(chainPanelContext.ToggleHabitCompletion))
-	gui.g.SetKeybinding(v.Name(), config.GetKey(heatmapKeys.EditHabit), gocui.ModNone, gui.wrappedHandler(chainPanelContext.UpdateHabit))
-	gui.g.SetKeybinding(v.Name(), config.GetKey(heatmapKeys.CreateHabit), gocui.ModNone, gui.wrappedHandler(chainPanelContext.AddHabit))
-	gui.g.SetKeybinding(v.Name(), config.GetKey(heatmapKeys.DeleteHabit), gocui.ModNone, gui.wrappedHandler(chainPanelContext.RemoveHabit))
-	gui.g.SetKeybinding(v.Name(), config.GetKey(gui.Config.Keybinding.Universal.Close), gocui.ModNone, gui.wrappedHandler(chainPanelContext.CloseChainPanel))
-
 	return chainPanelContext
 }
 
+func (self *ChainPanelContext) actionHandlers() actionHandlers {
+	return mergeActionHandlers(self.viewModel.list.actionHandlers(), actionHandlers{
+		"selectRange":     self.viewModel.list.ToggleRangeSelect,
+		"toggleHabit":     self.ToggleHabitCompletion,
+		"skipHabit":       self.ToggleHabitSkip,
+		"createHabit":     self.AddHabit,
+		"editHabit":       self.UpdateHabit,
+		"editNote":        self.EditNote,
+		"deleteHabit":     self.RemoveHabit,
+		"archiveHabit":    self.ToggleHabitArchive,
+		"moveHabitUp":     func() error { return self.MoveHabit(-1) },
+		"moveHabitDown":   func() error { return self.MoveHabit(1) },
+		"copyPreviousDay": self.CopyFromPreviousDay,
+		"copyFromDay":     self.CopyFromDay,
+		"copyToDays":      self.CopyHabitToDays,
+		"close":           self.CloseChainPanel,
+	})
+}
+
 func (self *ChainPanelContext) OpenChainPanel() error {
 	selectedDate := self.gui.GetDateFromHeatmapCursor()
 	if selectedDate.IsZero() {
@@ -78,6 +99,8 @@ func (self *ChainPanelContext) OpenChainPanel() error {
 
 	viewName := self.view.Name()
 	self.viewModel.selectedDay = selectedDate
+	self.viewModel.list.SetEmptyMessage("No habits for this day. Create one by presing the " + self.gui.Config.Keybinding.Heatmap.CreateHabit)
+	self.viewModel.list.CancelRangeSelect()
 	self.viewModel.list.RefreshOptions()
 	self.viewModel.list.Render()
 
@@ -92,6 +115,13 @@ func (self *ChainPanelContext) OpenChainPanel() error {
 }
 
 func (self *ChainPanelContext) CloseChainPanel() error {
+	// the first close only cancels the selected range
+	if self.viewModel.list.IsSelectingRange() {
+		self.viewModel.list.CancelRangeSelect()
+		self.viewModel.list.Render()
+		return nil
+	}
+
 	self.view.Clear()
 	self.view.Visible = false
 	if _, err := self.gui.g.SetCurrentView(self.gui.ViewHeatmap.Name()); err != nil {
@@ -110,24 +140,28 @@ func (self *ChainPanelContext) CloseChainPanel() error {
 	return nil
 }
 
+// Moves the selected habits to the trash
 func (self *ChainPanelContext) RemoveHabit() error {
-	selected := self.viewModel.list.GetSelected()
-	if selected == (SelectItem{}) {
+	selected := self.viewModel.list.GetSelectedItems()
+	if len(selected) == 0 {
 		return nil
 	}
 
-	if err := self.habitService.Delete(context.Background(), models.HabitId(selected.id)); err != nil {
+	if err := self.habitService.DeleteMany(context.Background(), selectedIds(selected)); err != nil {
 		return err
 	}
+	self.viewModel.list.CancelRangeSelect()
 	self.view.Clear()
 	self.viewModel.list.RefreshOptions()
 	self.viewModel.list.Render()
 	return nil
 }
 
+// Toggles the completion of the selected habits, they are all completed
+// unless every one of them is already completed
 func (self *ChainPanelContext) ToggleHabitCompletion() error {
-	selected := self.viewModel.list.GetSelected()
-	if selected == (SelectItem{}) {
+	selected := self.viewModel.list.GetSelectedItems()
+	if len(selected) == 0 {
 		return nil
 	}
 
@@ -135,17 +169,168 @@ func (self *ChainPanelContext) ToggleHabitCompletion() error {
 	if err != nil {
 		return err
 	}
-	habit, finded := lo.Find(chain.Habits, func(x *models.Habit) bool { return x.Id == models.HabitId(selected.id) })
-	if !finded {
-		return errors.New("not found")
+	ids := selectedIds(selected)
+	completed := lo.SomeBy(chain.Habits, func(x *models.Habit) bool { return lo.Contains(ids, x.Id) && !x.IsCompleted })
+	if err := self.habitService.SetCompleted(context.Background(), ids, completed); err != nil {
+		return err
+	}
+	self.viewModel.list.CancelRangeSelect()
+	self.view.Clear()
+	self.viewModel.list.RefreshOptions()
+	self.viewModel.list.Render()
+	return nil
+}
+
+// Toggles the skip of the selected habits, they are all skipped unless every
+// one of them is already skipped
+func (self *ChainPanelContext) ToggleHabitSkip() error {
+	selected := self.viewModel.list.GetSelectedItems()
+	if len(selected) == 0 {
+		return nil
+	}
+
+	chain, err := self.habitService.GetAllByDay(context.Background(), self.viewModel.selectedDay)
+	if err != nil {
+		return err
+	}
+	ids := selectedIds(selected)
+	skipped := lo.SomeBy(chain.Habits, func(x *models.Habit) bool { return lo.Contains(ids, x.Id) && !x.IsSkipped })
+	if err := self.habitService.SetSkipped(context.Background(), ids, skipped); err != nil {
+		return err
+	}
+	self.viewModel.list.CancelRangeSelect()
+	self.view.Clear()
+	self.viewModel.list.RefreshOptions()
+	self.viewModel.list.Render()
+	return nil
+}
+
+// Toggles the archive of the selected habits, they are all archived unless
+// every one of them is already archived
+func (self *ChainPanelContext) ToggleHabitArchive() error {
+	selected := self.viewModel.list.GetSelectedItems()
+	if len(selected) == 0 {
+		return nil
+	}
+
+	chain, err := self.habitService.GetAllByDay(context.Background(), self.viewModel.selectedDay)
+	if err != nil {
+		return err
+	}
+	ids := selectedIds(selected)
+	archived := lo.SomeBy(chain.Habits, func(x *models.Habit) bool { return lo.Contains(ids, x.Id) && !x.IsArchived() })
+	for _, id := range ids {
+		if err := self.habitService.Archive(context.Background(), id, archived); err != nil {
+			return err
+		}
+	}
+	self.viewModel.list.CancelRangeSelect()
+	self.view.Clear()
+	self.viewModel.list.RefreshOptions()
+	self.viewModel.list.Render()
+	return nil
+}
+
+// Moves the selected habit up or down, the selection follows the habit. A
+// range of habits is not moved.
+func (self *ChainPanelContext) MoveHabit(offset int) error {
+	if self.viewModel.list.IsSelectingRange() {
+		self.viewModel.list.CancelRangeSelect()
+		self.viewModel.list.Render()
+		self.gui.showStatusMessage("Move the habits one at a time")
+		return nil
+	}
+	selected := self.viewModel.list.GetSelected()
+	if selected == (SelectItem{}) {
+		return nil
+	}
+
+	if err := self.habitService.Move(context.Background(), models.HabitId(selected.id), offset); err != nil {
+		return err
 	}
-	habit.ToggleCompletion()
-	if err := self.habitService.Update(context.Background(), habit); err != nil {
+	self.view.Clear()
+	self.viewModel.list.RefreshOptions()
+	if offset < 0 {
+		return self.viewModel.list.HandlePrevLine()
+	}
+	return self.viewModel.list.HandleNextLine()
+}
+
+func (self *ChainPanelContext) CopyFromPreviousDay() error {
+	created, err := self.copyFrom(self.viewModel.selectedDay.AddDate(0, 0, -1))
+	if err != nil {
 		return err
 	}
 	self.view.Clear()
 	self.viewModel.list.RefreshOptions()
 	self.viewModel.list.Render()
+	self.gui.showStatusMessage(fmt.Sprintf("Copied %d habits", created))
+	return nil
+}
+
+func (self *ChainPanelContext) CopyFromDay() error {
+	onConfirm := func(input string) error {
+		days, err := utils.ParseDays(input)
+		if err != nil || len(days) != 1 {
+			self.gui.showStatusMessage(fmt.Sprintf("invalid day %q, use the YYYY-MM-DD format", input))
+			return nil
+		}
+		created, err := self.copyFrom(days[0])
+		if err != nil {
+			return err
+		}
+		self.gui.showStatusMessage(fmt.Sprintf("Copied %d habits", created))
+		return self.gui.HabitsPanel.CloseHabitPanel()
+	}
+	previousDay := self.viewModel.selectedDay.AddDate(0, 0, -1).Format(time.DateOnly)
+	return self.openHabitPanel(previousDay, "Copy habits from day", onConfirm)
+}
+
+// Copies the selected habits to a day or a range of days
+func (self *ChainPanelContext) CopyHabitToDays() error {
+	selected := self.viewModel.list.GetSelectedItems()
+	if len(selected) == 0 {
+		return nil
+	}
+
+	onConfirm := func(input string) error {
+		days, err := utils.ParseDays(input)
+		if err != nil {
+			self.gui.showStatusMessage(err.Error())
+			return nil
+		}
+		created, err := self.habitService.Copy(context.Background(), selectedIds(selected), days)
+		if err != nil {
+			return err
+		}
+		self.viewModel.list.CancelRangeSelect()
+		self.gui.showStatusMessage(fmt.Sprintf("Copied %d habits", created))
+		return self.gui.HabitsPanel.CloseHabitPanel()
+	}
+	nextDay := self.viewModel.selectedDay.AddDate(0, 0, 1).Format(time.DateOnly)
+	return self.openHabitPanel(nextDay, fmt.Sprintf("Copy %d habits to days, from to", len(selected)), onConfirm)
+}
+
+// Copies all the habits of the day to the selected day
+func (self *ChainPanelContext) copyFrom(day time.Time) (int, error) {
+	chain, err := self.habitService.GetAllByDay(context.Background(), day)
+	if err != nil {
+		return 0, err
+	}
+	ids := lo.Map(chain.Habits, func(x *models.Habit, _ int) models.HabitId { return x.Id })
+	return self.habitService.Copy(context.Background(), ids, []time.Time{self.viewModel.selectedDay})
+}
+
+func (self *ChainPanelContext) openHabitPanel(text string, title string, onConfirm func(string) error) error {
+	self.gui.HabitsPanel.SetPanelState(0, text, title, onConfirm)
+	viewName := self.gui.HabitsPanel.view.Name()
+	if _, err := self.gui.g.SetViewOnTop(viewName); err != nil {
+		return err
+	}
+	if _, err := self.gui.g.SetCurrentView(viewName); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -187,6 +372,34 @@ func (self *ChainPanelContext) UpdateHabit() error {
 	return nil
 }
 
+func (self *ChainPanelContext) EditNote() error {
+	selected := self.viewModel.list.GetSelected()
+	if selected == (SelectItem{}) {
+		return nil
+	}
+
+	chain, err := self.habitService.GetAllByDay(context.Background(), self.viewModel.selectedDay)
+	if err != nil {
+		return err
+	}
+	habit, finded := lo.Find(chain.Habits, func(x *models.Habit) bool { return x.Id == models.HabitId(selected.id) })
+	if !finded {
+		return errors.New("not found")
+	}
+
+	onConfirm := func(note string) error {
+		if err := habit.ChangeNote(note); err != nil {
+			self.gui.showStatusMessage(app.ErrorMessage(err))
+			return nil
+		}
+		if err := self.habitService.Update(context.Background(), habit); err != nil {
+			return err
+		}
+		return self.gui.HabitsPanel.CloseHabitPanel()
+	}
+	return self.openHabitPanel(habit.Note, fmt.Sprintf("Note of %s", habit.Title), onConfirm)
+}
+
 func (self *ChainPanelContext) AddHabit() error {
 
 	onConfirm := func(newtitle string) error {
@@ -215,3 +428,7 @@ func (self *ChainPanelContext) AddHabit() error {
 
 	return nil
 }
+
+func selectedIds(items []SelectItem) []models.HabitId {
+	return lo.Map(items, func(x SelectItem, _ int) models.HabitId { return models.HabitId(x.id) })
+}
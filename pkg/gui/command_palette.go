@@ -0,0 +1,199 @@
+package gui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/metagunner/habheat/pkg/config"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/samber/lo"
+)
+
+// the views of the main window, their actions can be run from each other
+var mainViews = []string{"years", "heatmap", "chainpanel"}
+
+type CommandPaletteContext struct {
+	// the filter is typed into the view and the actions are listed in the list view
+	view      *gocui.View
+	listView  *gocui.View
+	viewModel *CommandPaletteViewModel
+	gui       *Gui
+}
+
+type CommandPaletteViewModel struct {
+	list *SelectList
+	// actions of the listed items, the ids of the items are their indexes
+	actions []paletteAction
+	// the view that was focused when the palette is opened
+	previousView string
+}
+
+// an action listed in the command palette with every key it is bound to
+type paletteAction struct {
+	Action
+	keys []string
+}
+
+func NewCommandPaletteContext(v *gocui.View, listView *gocui.View, gui *Gui) *CommandPaletteContext {
+	viewModel := &CommandPaletteViewModel{}
+	commandPaletteContext := &CommandPaletteContext{
+		view:      v,
+		listView:  listView,
+		viewModel: viewModel,
+		gui:       gui,
+	}
+
+	getDisplayStrings := func() []SelectItem {
+		viewModel.actions = commandPaletteContext.filterActions(strings.TrimSpace(v.TextArea.GetContent()))
+		result := make([]SelectItem, 0, len(viewModel.actions))
+		for i, action := range viewModel.actions {
+			result = append(result, SelectItem{id: i, option: fmt.Sprintf("%-14s %-10s %s", strings.Join(action.keys, " "), action.ViewTitle, action.Description)})
+		}
+		return result
+	}
+	viewModel.list = NewSelectList(gui, listView, getDisplayStrings)
+	viewModel.list.SetEmptyMessage("No actions match")
+
+	// the list is filtered on every key typed into the view
+	v.Editor = gocui.EditorFunc(func(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) bool {
+		matched := gocui.DefaultEditor.Edit(v, key, ch, mod)
+		viewModel.list.Reset()
+		viewModel.list.RefreshOptions()
+		viewModel.list.Render()
+		return matched
+	})
+
+	return commandPaletteContext
+}
+
+func (self *CommandPaletteContext) actionHandlers() actionHandlers {
+	return mergeActionHandlers(self.viewModel.list.actionHandlers(), actionHandlers{
+		"confirm": self.RunAction,
+		"close":   self.CloseCommandPalette,
+	})
+}
+
+// Returns the actions that can be run from the previous view, the best matches
+// of the filter are the first
+func (self *CommandPaletteContext) filterActions(filter string) []paletteAction {
+	previousView := self.viewModel.previousView
+	views := []string{previousView, ""}
+	if slices.Contains(mainViews, previousView) {
+		views = append(views, mainViews...)
+	}
+
+	// the actions bound to more than one key are listed once
+	actions := []paletteAction{}
+	indexByName := map[string]int{}
+	for _, view := range lo.Uniq(views) {
+		for _, action := range self.gui.actions {
+			label := config.GetKeyLabel(action.Key)
+			if action.View != view || label == "" {
+				continue
+			}
+			name := action.name()
+			if i, ok := indexByName[name]; ok {
+				actions[i].keys = append(actions[i].keys, label)
+				continue
+			}
+			indexByName[name] = len(actions)
+			actions = append(actions, paletteAction{Action: action, keys: []string{label}})
+		}
+	}
+	if filter == "" {
+		return actions
+	}
+
+	scores := map[string]int{}
+	actions = lo.Filter(actions, func(action paletteAction, _ int) bool {
+		score, ok := utils.FuzzyScore(filter, action.Description+" "+action.ViewTitle)
+		scores[action.name()] = score
+		return ok
+	})
+	slices.SortStableFunc(actions, func(a, b paletteAction) int {
+		return scores[b.name()] - scores[a.name()]
+	})
+	return actions
+}
+
+func (self *CommandPaletteContext) OpenCommandPalette() error {
+	currentView := self.gui.g.CurrentView()
+	if currentView == nil || currentView.Name() == self.view.Name() {
+		return nil
+	}
+
+	self.viewModel.previousView = currentView.Name()
+	self.listView.Subtitle = lo.Ternary(currentView.Title != "", currentView.Title, currentView.Name())
+	self.gui.g.Cursor = true
+	self.view.Visible = true
+	self.view.ClearTextArea()
+	self.view.RenderTextArea()
+	self.listView.Clear()
+	self.listView.Visible = true
+	self.viewModel.list.Reset()
+	self.viewModel.list.RefreshOptions()
+	self.viewModel.list.Render()
+
+	if _, err := self.gui.g.SetViewOnTop(self.listView.Name()); err != nil {
+		return err
+	}
+	viewName := self.view.Name()
+	if _, err := self.gui.g.SetViewOnTop(viewName); err != nil {
+		return err
+	}
+	if _, err := self.gui.g.SetCurrentView(viewName); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (self *CommandPaletteContext) CloseCommandPalette() error {
+	self.view.ClearTextArea()
+	self.view.Clear()
+	self.view.Visible = false
+	self.listView.Clear()
+	self.listView.Visible = false
+	self.gui.g.Cursor = false
+	if _, err := self.gui.g.SetCurrentView(self.viewModel.previousView); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Runs the selected action in its view
+func (self *CommandPaletteContext) RunAction() error {
+	if len(self.viewModel.actions) == 0 {
+		return nil
+	}
+	action := self.viewModel.actions[self.viewModel.list.GetSelected().id]
+
+	if err := self.CloseCommandPalette(); err != nil {
+		return err
+	}
+	if err := self.focusView(action.View); err != nil {
+		return err
+	}
+	// e.g. the habits are not shown when the heat map cursor is not on a day
+	if currentView := self.gui.g.CurrentView(); action.View != "" && (currentView == nil || currentView.Name() != action.View) {
+		return nil
+	}
+	return action.Handler()
+}
+
+// focuses the view of the action when it is not the previous view
+func (self *CommandPaletteContext) focusView(viewName string) error {
+	if viewName == "" || viewName == self.viewModel.previousView {
+		return nil
+	}
+
+	chainPanel := self.gui.ChainPanel
+	if viewName == chainPanel.view.Name() && !chainPanel.view.Visible {
+		// the habits of the day under the heat map cursor
+		return chainPanel.OpenChainPanel()
+	}
+	return self.gui.nextWindow(viewName)
+}
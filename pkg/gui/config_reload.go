@@ -0,0 +1,98 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/metagunner/habheat/pkg/config"
+	"github.com/metagunner/habheat/pkg/utils"
+)
+
+const statusMessageDuration = 5 * time.Second
+
+// Loads the config file again and applies the theme and the keybindings.
+// The current config is kept when the new one can't be loaded or is invalid.
+func (gui *Gui) reloadConfig() {
+	newConfig, err := config.LoadUserConfig(gui.configFilePath, config.GetDefaultConfig())
+	if err == nil {
+		err = config.Validate(newConfig)
+	}
+	if err != nil {
+		message, _, _ := strings.Cut(err.Error(), "\n")
+		var validationErr *config.ValidationError
+		if errors.As(err, &validationErr) {
+			message = validationErr.Problems[0]
+		}
+		gui.showStatusMessage(fmt.Sprintf("Config is not reloaded, run `habheat config check`: %s", message))
+		return
+	}
+
+	previousConfig := gui.Config
+	gui.Config = newConfig
+	if err := gui.applyConfig(); err != nil {
+		gui.Config = previousConfig
+		_ = gui.applyConfig()
+		gui.showStatusMessage(fmt.Sprintf("Config is not reloaded: %s", err))
+		return
+	}
+
+	gui.showStatusMessage("Config reloaded")
+}
+
+func (gui *Gui) applyConfig() error {
+	utils.SetDayClock(gui.Config.Gui.GetDayClock())
+	gui.applyBorderColors()
+	gui.renderLegend()
+
+	gui.g.DeleteAllKeybindings()
+	if err := gui.setKeybindings(); err != nil {
+		return err
+	}
+
+	if err := gui.reInitGrid(gui.YearsSelectList.GetSelected().option); err != nil {
+		return err
+	}
+	return gui.renderHeatmap()
+}
+
+func (gui *Gui) applyBorderColors() {
+	gui.g.FgColor = config.GetGocuiStyle(gui.Config.Gui.Theme.InactiveBorderColor)
+	gui.g.SelFgColor = config.GetGocuiStyle(gui.Config.Gui.Theme.ActiveBorderColor)
+	gui.g.FrameColor = config.GetGocuiStyle(gui.Config.Gui.Theme.InactiveBorderColor)
+	gui.g.SelFrameColor = config.GetGocuiStyle(gui.Config.Gui.Theme.ActiveBorderColor)
+}
+
+// renders the shades of the selected color scheme in the colors view, the
+// view is resized for the number of shades
+func (gui *Gui) renderLegend() {
+	color := gui.GetColorScheme()
+	maxX, _ := gui.g.Size()
+	width := len("Less ") + 2*color.Levels() + len(" More")
+	colorsV, err := gui.g.SetView("colors", maxX-width-3, 0, maxX-2, 2, 0)
+	if err != nil && !gocui.IsUnknownView(err) {
+		return
+	}
+	colorsV.Clear()
+	fmt.Fprint(colorsV, "Less ")
+	for i := 1; i <= color.Levels(); i++ {
+		fmt.Fprint(colorsV, color.StatusValues[i])
+	}
+	fmt.Fprint(colorsV, " More")
+}
+
+// shows the message in the status view for a while
+func (gui *Gui) showStatusMessage(message string) {
+	gui.statusMessage = message
+	if gui.statusMessageTimer != nil {
+		gui.statusMessageTimer.Stop()
+	}
+	gui.statusMessageTimer = time.AfterFunc(statusMessageDuration, func() {
+		gui.g.Update(func(*gocui.Gui) error {
+			gui.statusMessage = ""
+			return nil
+		})
+	})
+}
@@ -4,14 +4,16 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"math"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/jesseduffield/gocui"
 	"github.com/metagunner/habheat/pkg/app"
 	"github.com/metagunner/habheat/pkg/config"
 	"github.com/metagunner/habheat/pkg/database"
+	"github.com/metagunner/habheat/pkg/heatmap"
 	"github.com/metagunner/habheat/pkg/models"
 	"github.com/metagunner/habheat/pkg/utils"
 	"github.com/samber/lo"
@@ -24,24 +26,54 @@ type Gui struct {
 	YearsSelectList   *SelectList
 	ChainPanel        *ChainPanelContext
 	HabitsPanel       *HabitPanelContext
+	HelpPanel         *HelpPanelContext
+	TrashPanel        *TrashPanelContext
+	SearchPanel       *SearchPanelContext
+	CommandPalette    *CommandPaletteContext
 	mustRenderHeatmap bool
 	HabitService      models.HabitService
 	heatmapFirstDate  time.Time
 	heatmapLastDate   time.Time
 	Config            *config.UserConfig
+	configFilePath    string
+	colorProfile      config.ColorProfile
 	StatusView        *gocui.View
 	version           string
+	// temporary message shown in the status view instead of the keybinding hints
+	statusMessage      string
+	statusMessageTimer *time.Timer
+	// streak of the days shown in the heat map
+	streak models.Streak
+	// months shown above the columns of the heat map where they start
+	monthLabels []heatmap.MonthLabel
+	// first day of the range selected in the heat map, zero when no range is selected
+	rangeStart time.Time
+	// every action of the keybindings, listed in the command palette
+	actions []Action
+	// key sequences of the actions by their views
+	keybindings map[string][]keybinding
+	// typed keys that start a key sequence and the view they are typed in
+	pendingKeys []config.Key
+	pendingView string
+	// count typed before a motion, e.g. 4 of 4l, zero when no count is typed
+	count int
+	// global action of the digit that started the count, e.g. 1 focuses the
+	// years, it runs when no key follows the digit for a while
+	countAction      *keybinding
+	countActionTimer *time.Timer
 }
 
 type HeatGrid struct {
-	row                 int
-	column              int
-	key                 time.Time
-	rank                int
-	shade               string
-	totalNumberOfHabits int
-	completedHabits     int
-	haveInfo            bool
+	row    int
+	column int
+	// zero for the cells that are not a day of the heat map
+	key   time.Time
+	shade string
+	cell  heatmap.Cell
+}
+
+func newHeatGrid(cell heatmap.Cell, theme config.HeatmapColorScheme) *HeatGrid {
+	return &HeatGrid{row: cell.Row, column: cell.Column, key: cell.Day, shade: heatmap.Shade(cell, theme), cell: cell}
 }
 
 var (
@@ -51,8 +83,8 @@ var (
 	newVersionAvailable bool
 )
 
-func NewGui(config *config.UserConfig, db *database.DB, version string) *Gui {
-	return &Gui{Config: config, db: db, version: version}
+func NewGui(config *config.UserConfig, configFilePath string, db *database.DB, version string) *Gui {
+	return &Gui{Config: config, configFilePath: configFilePath, db: db, version: version}
 }
 
 func (gui *Gui) initGocui() (*gocui.Gui, error) {
@@ -76,10 +108,9 @@ func (gui *Gui) Run() error {
 	gui.g = g
 	defer gui.g.Close()
 
-	gui.g.FgColor = config.GetGocuiStyle(gui.Config.Gui.Theme.InactiveBorderColor)
-	gui.g.SelFgColor = config.GetGocuiStyle(gui.Config.Gui.Theme.ActiveBorderColor)
-	gui.g.FrameColor = config.GetGocuiStyle(gui.Config.Gui.Theme.InactiveBorderColor)
-	gui.g.SelFrameColor = config.GetGocuiStyle(gui.Config.Gui.Theme.ActiveBorderColor)
+	gui.colorProfile = config.DetectColorProfile()
+
+	gui.applyBorderColors()
 
 	gui.g.SetManager(gocui.ManagerFunc(gui.layout))
 
@@ -96,56 +127,33 @@ func (gui *Gui) Run() error {
 		return err
 	}
 
-	gui.setKeybindings()
-
-	newVersionAvailable = app.CheckForNewUpdate(gui.version)
-
-	return gui.g.MainLoop()
-}
-
-func (gui *Gui) setKeybindings() error {
-	var err error
-	err = gui.g.SetKeybinding("", config.GetKey(gui.Config.Keybinding.Universal.Quit), gocui.ModNone, quit)
-	if err != nil {
+	if err := gui.setKeybindings(); err != nil {
 		return err
 	}
 
-	err = gui.g.SetKeybinding("", '1', gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		return gui.nextWindow("years")
+	watcher, err := config.WatchUserConfig(gui.configFilePath, func() {
+		gui.g.Update(func(*gocui.Gui) error {
+			gui.reloadConfig()
+			return nil
+		})
 	})
 	if err != nil {
-		return err
+		// gocui owns the terminal, a logged error would be drawn over the UI
+		gui.showStatusMessage(fmt.Sprintf("Config changes are not watched: %s", err))
+	} else {
+		defer watcher.Close()
 	}
 
-	err = gui.g.SetKeybinding("", '2', gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		return gui.nextWindow("heatmap")
-	})
-	if err != nil {
-		return err
-	}
+	newVersionAvailable = app.CheckForNewUpdate(gui.version)
 
-	heatmapKeys := gui.Config.Keybinding.Heatmap
-	gui.g.SetKeybinding("heatmap", config.GetKey(heatmapKeys.UpAlt), gocui.ModNone, moveCursor(gui, -1, 0))
-	gui.g.SetKeybinding("heatmap", config.GetKey(heatmapKeys.DownAlt), gocui.ModNone, moveCursor(gui, 1, 0))
-	gui.g.SetKeybinding("heatmap", config.GetKey(heatmapKeys.LeftAlt), gocui.ModNone, moveCursor(gui, 0, -1))
-	gui.g.SetKeybinding("heatmap", config.GetKey(heatmapKeys.RightAlt), gocui.ModNone, moveCursor(gui, 0, 1))
-	gui.g.SetKeybinding("heatmap", config.GetKey(heatmapKeys.Up), gocui.ModNone, moveCursor(gui, -1, 0))
-	gui.g.SetKeybinding("heatmap", config.GetKey(heatmapKeys.Down), gocui.ModNone, moveCursor(gui, 1, 0))
-	gui.g.SetKeybinding("heatmap", config.GetKey(heatmapKeys.Left), gocui.ModNone, moveCursor(gui, 0, -1))
-	gui.g.SetKeybinding("heatmap", config.GetKey(heatmapKeys.Right), gocui.ModNone, moveCursor(gui, 0, 1))
-	gui.g.SetKeybinding("heatmap", config.GetKey(gui.Config.Keybinding.Universal.Select), gocui.ModNone, gui.wrappedHandler(gui.ChainPanel.OpenChainPanel))
-
-	err = gui.g.SetKeybinding("years", config.GetKey(gui.Config.Keybinding.Universal.Select), gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		selected := gui.YearsSelectList.GetSelected().option
-		gui.reInitGrid(selected)
-		gui.renderHeatmap()
-		return nil
-	})
-	if err != nil {
-		return err
-	}
+	return gui.g.MainLoop()
+}
 
-	return nil
+// shows the heat map of the selected year
+func (gui *Gui) selectYear() error {
+	selected := gui.YearsSelectList.GetSelected().option
+	gui.reInitGrid(selected)
+	return gui.renderHeatmap()
 }
 
 func (gui *Gui) nextWindow(viewName string) error {
@@ -160,7 +168,22 @@ func (gui *Gui) nextWindow(viewName string) error {
 	return nil
 }
 
-func quit(g *gocui.Gui, v *gocui.View) error {
+// switches between the years and the heat map, the popups keep the focus
+func (gui *Gui) focusNextWindow() error {
+	currentView := gui.g.CurrentView()
+	if currentView == nil {
+		return nil
+	}
+	switch currentView.Name() {
+	case "years":
+		return gui.nextWindow("heatmap")
+	case "heatmap":
+		return gui.nextWindow("years")
+	}
+	return nil
+}
+
+func quit() error {
 	return gocui.ErrQuit
 }
 
@@ -172,231 +195,318 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 
 	gui.renderHeatmap()
 	gui.g.SetViewOnTop("colors")
-	gui.renderVersion()
+	gui.renderStatus()
 	return nil
 }
 
-func (gui *Gui) renderVersion() {
+// renders the keybinding hints of the focused view and the version
+func (gui *Gui) renderStatus() {
 	gui.StatusView.Clear()
 	newVersionText := lo.Ternary(newVersionAvailable, "new version available!", "")
-	fmt.Fprintf(gui.StatusView, "%s %s", gui.version, newVersionText)
+	versionText := strings.TrimSpace(fmt.Sprintf("%s %s", gui.version, newVersionText))
+
+	hints := []string{}
+	if currentView := gui.g.CurrentView(); currentView != nil {
+		for _, group := range gui.GetActiveBindings(currentView.Name()) {
+			for _, binding := range group.Bindings {
+				if binding.Navigation || (group.View == "" && binding.Key != gui.Config.Keybinding.Universal.OpenHelp) {
+					continue
+				}
+				label := config.GetKeyLabel(binding.Key)
+				if label == "" {
+					continue
+				}
+				hints = append(hints, fmt.Sprintf("%s: %s", label, strings.ToLower(binding.Description)))
+			}
+		}
+	}
+	hintsText := strings.Join(hints, " | ")
+	if gui.statusMessage != "" {
+		hintsText = gui.statusMessage
+		hints = []string{gui.statusMessage}
+	}
+	// the typed count and keys followed by the sequences that can complete them
+	if pendingText := gui.getPendingText(); pendingText != "" {
+		if pending := gui.getPendingKeybindings(); len(pending) > 0 {
+			hints = lo.Map(pending, func(binding keybinding, _ int) string {
+				return fmt.Sprintf("%s: %s", config.GetSequenceLabel(binding.keys), strings.ToLower(binding.action.Description))
+			})
+		}
+		hints = append([]string{pendingText}, hints...)
+		hintsText = strings.Join(hints, " | ")
+	}
+
+	width := gui.StatusView.InnerWidth()
+	maxHintsWidth := width - utf8.RuneCountInString(versionText) - 1
+	if utf8.RuneCountInString(hintsText) > maxHintsWidth {
+		hintsText = ""
+		if maxHintsWidth > 3 {
+			hintsText = string([]rune(strings.Join(hints, " | "))[:maxHintsWidth-3]) + "..."
+		}
+	}
+	padding := lo.Max([]int{1, width - utf8.RuneCountInString(hintsText) - utf8.RuneCountInString(versionText)})
+	fmt.Fprintf(gui.StatusView, "%s%s%s", hintsText, strings.Repeat(" ", padding), versionText)
 }
 
 func (gui *Gui) renderHeatmap() error {
 	v := gui.ViewHeatmap
 	v.Clear()
 
-	months := utils.GetMonths(gui.heatmapLastDate)
-	for _, month := range months {
-		monthName := fmt.Sprintf("   %s   ", month.Format("Jan"))
-		fmt.Fprint(v, monthName)
-	}
-	fmt.Fprintln(v)
+	locale := gui.GetLocale()
+	fmt.Fprintln(v, formatMonthLabels(gui.monthLabels, len(grid[0]), locale))
 
-	defaultTheme := gui.Config.Gui.Theme.Selected
-	theme := gui.Config.Gui.Theme.ColorSchemes[defaultTheme]
+	theme := gui.GetColorScheme()
 
-	labels := []string{"   ", "Mon", "   ", "Wed", "   ", "Fri", "   "}
-	printLabel := 0
+	weekStart := gui.Config.Gui.GetWeekStart()
 	// Print the grid
-	for _, row := range grid {
-		fmt.Fprintf(v, "%s  ", labels[printLabel])
+	for i, row := range grid {
+		label := "   "
+		weekday := time.Weekday((int(weekStart) + i) % 7)
+		if weekday == time.Monday || weekday == time.Wednesday || weekday == time.Friday {
+			label = padLabel(locale.WeekdayName(weekday), 3)
+		}
+		fmt.Fprintf(v, "%s", padLabel(label, weekdayLabelWidth))
 		for _, slot := range row {
 			if slot.row == cursorY && slot.column == cursorX {
 				fmt.Fprintf(v, "%s", theme.CursorValue)
+			} else if gui.isInSelectedRange(slot.key) {
+				fmt.Fprintf(v, "%s", theme.SelectedValue)
 			} else {
 				fmt.Fprintf(v, "%s", slot.shade)
 			}
 		}
-		printLabel++
 		fmt.Fprintln(v)
 	}
 
 	fmt.Fprintln(v)
 	info := grid[cursorY][cursorX]
 	if !info.key.IsZero() {
-		if info.haveInfo {
-			fmt.Fprintf(v, "%d/%d habits on %s %s", info.completedHabits, info.totalNumberOfHabits, info.key.Format("Jan"), utils.GetOrdinalSuffix(info.key.Day()))
-		} else {
-			fmt.Fprintf(v, "No habits on %s %s", info.key.Format("Jan"), utils.GetOrdinalSuffix(info.key.Day()))
-		}
+		fmt.Fprint(v, heatmap.Describe(info.cell, locale))
 	}
+	fmt.Fprintf(v, "\nCurrent streak %d days, longest streak %d days", gui.streak.Current, gui.streak.Longest)
 
 	return nil
 }
 
+// the width of the weekday labels and of a day in the heat map
+const (
+	weekdayLabelWidth = 5
+	dayWidth          = 2
+)
+
+// Returns the month names above the columns of the heat map where the months
+// start, like the month labels of the exported images
+func formatMonthLabels(labels []heatmap.MonthLabel, columns int, locale utils.Locale) string {
+	line := []rune(strings.Repeat(" ", weekdayLabelWidth+columns*dayWidth))
+	for _, label := range labels {
+		copy(line[weekdayLabelWidth+label.Column*dayWidth:], []rune(padLabel(locale.MonthName(label.Month), 4)))
+	}
+	return strings.TrimRight(string(line), " ")
+}
+
 func (gui *Gui) wrappedHandler(f func() error) func(g *gocui.Gui, v *gocui.View) error {
 	return func(g *gocui.Gui, v *gocui.View) error {
 		return f()
 	}
 }
 
-func moveCursor(g *Gui, dy, dx int) func(*gocui.Gui, *gocui.View) error {
-	return func(gui *gocui.Gui, v *gocui.View) error {
-		// Calculate new cursor position
-		newCursorX := cursorX + dx
-		newCursorY := cursorY + dy
-
-		// Ensure cursor stays within grid bounds
-		if newCursorX < 0 {
-			newCursorX = 0
-		} else if newCursorX >= 53 {
-			newCursorX = 52
-		}
-		if newCursorY < 0 {
-			newCursorY = 0
-		} else if newCursorY >= 7 {
-			newCursorY = 6
-		}
-
-		// Update cursor position
-		cursorX = newCursorX
-		cursorY = newCursorY
+// starts selecting a range of days from the day under the cursor, or stops it
+func (gui *Gui) toggleRangeSelect() error {
+	if gui.rangeStart.IsZero() {
+		gui.rangeStart = gui.GetDateFromHeatmapCursor()
+	} else {
+		gui.rangeStart = time.Time{}
+	}
+	return gui.renderHeatmap()
+}
 
-		g.renderHeatmap()
+func (gui *Gui) cancelRangeSelect() error {
+	gui.rangeStart = time.Time{}
+	return gui.renderHeatmap()
+}
 
-		return nil
+func (gui *Gui) isInSelectedRange(day time.Time) bool {
+	cursorDay := gui.GetDateFromHeatmapCursor()
+	if gui.rangeStart.IsZero() || cursorDay.IsZero() || day.IsZero() {
+		return false
+	}
+	from, to := gui.rangeStart, cursorDay
+	if to.Before(from) {
+		from, to = to, from
 	}
+	return !day.Before(from) && !day.After(to)
 }
 
-// Init grid for the default view
-func (gui *Gui) initializeGrid() {
-	// Get today's date
-	now := time.Now()
-	today := utils.CreateDate(now.Year(), now.Month(), now.Day())
+// Returns the first and the last day of the selected range, or the day under
+// the cursor. Both are zero when the cursor is not on a day.
+func (gui *Gui) getSelectedRange() (time.Time, time.Time) {
+	cursorDay := gui.GetDateFromHeatmapCursor()
+	if cursorDay.IsZero() {
+		return time.Time{}, time.Time{}
+	}
+	from, to := cursorDay, cursorDay
+	if !gui.rangeStart.IsZero() {
+		from, to = gui.rangeStart, cursorDay
+		if to.Before(from) {
+			from, to = to, from
+		}
+	}
+	return from, to
+}
 
-	if today.Weekday() == time.Sunday {
-		today = today.AddDate(0, 0, 7)
+// Returns the days of the selected range up to today, or the day under the cursor
+func (gui *Gui) getSelectedDays() []time.Time {
+	from, to := gui.getSelectedRange()
+	if from.IsZero() {
+		return []time.Time{}
 	}
 
-	// Find the next upcoming Sunday after today
-	for today.Weekday() != time.Sunday {
-		today = today.AddDate(0, 0, 1)
+	days := []time.Time{}
+	for day := from; !day.After(to) && !day.After(utils.Today()); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
 	}
+	return days
+}
 
-	// Calculate the start date: 53 weeks before the next upcoming Sunday
-	startDate := today.AddDate(0, 0, -53*7)
+// Asks for a habit and marks it done on the selected days
+func (gui *Gui) markHabitDone() error {
+	days := gui.getSelectedDays()
+	if len(days) == 0 {
+		return nil
+	}
 
-	// Find the first Sunday on or after the start date
-	firstSunday := startDate
-	for firstSunday.Weekday() != time.Sunday {
-		firstSunday = firstSunday.AddDate(0, 0, 1)
+	suggestion, err := gui.suggestHabit(days[0])
+	if err != nil {
+		return err
 	}
-	startDate = firstSunday
 
-	gui.heatmapFirstDate = today
-	gui.heatmapLastDate = startDate
+	onConfirm := func(newtitle string) error {
+		title, err := models.CreateHabitTitle(newtitle)
+		if err != nil {
+			gui.showStatusMessage(app.ErrorMessage(err))
+			return nil
+		}
+		changed, err := gui.HabitService.Complete(context.Background(), title, days)
+		if err != nil {
+			return err
+		}
+		gui.rangeStart = time.Time{}
+		gui.showStatusMessage(fmt.Sprintf("Marked %s done on %d days", title, changed))
+		if err := gui.HabitsPanel.CloseHabitPanel(); err != nil {
+			return err
+		}
+		if err := gui.reInitGrid(gui.YearsSelectList.GetSelected().option); err != nil {
+			return err
+		}
+		return gui.renderHeatmap()
+	}
+	return gui.ChainPanel.openHabitPanel(suggestion, fmt.Sprintf("Mark habit done on %d days", len(days)), onConfirm)
+}
 
-	// Create the grid
-	grid = make([][]*HeatGrid, 7)
-	for i := range grid {
-		grid[i] = make([]*HeatGrid, 53)
+// Asks for a habit and its new title and renames the habit on the selected
+// days, the future days included. The habit is not renamed on the other days.
+func (gui *Gui) renameHabit() error {
+	from, to := gui.getSelectedRange()
+	if from.IsZero() {
+		return nil
 	}
 
-	defaultTheme := gui.Config.Gui.Theme.Selected
-	theme := gui.Config.Gui.Theme.ColorSchemes[defaultTheme]
-	heatmaps, _, err := gui.HabitService.HeatMap(context.Background(), startDate, today)
+	suggestion, err := gui.suggestHabit(from)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	// Fill the grid with colored boxes
-	today = utils.CreateDate(now.Year(), now.Month(), now.Day())
-	currentDate := startDate
-	for col := 0; col < 53; col++ {
-		for row := 0; row < 7; row++ {
-			heatGrid := &HeatGrid{
-				row:    row,
-				column: col,
-				key:    currentDate,
+	onConfirm := func(oldTitle string) error {
+		habits, err := gui.HabitService.ListByRange(context.Background(), from, to)
+		if err != nil {
+			return err
+		}
+		ids := lo.FilterMap(habits, func(x *models.Habit, _ int) (models.HabitId, bool) { return x.Id, x.Title.String() == oldTitle })
+		if len(ids) == 0 {
+			gui.showStatusMessage(fmt.Sprintf("%s is not tracked on the selected days", oldTitle))
+			return nil
+		}
+
+		onRename := func(newtitle string) error {
+			title, err := models.CreateHabitTitle(newtitle)
+			if err != nil {
+				gui.showStatusMessage(app.ErrorMessage(err))
+				return nil
 			}
-			if currentDate.After(today) {
-				// grid[row][col] = fmt.Sprintf("\x1b[48;5;52m%d \x1b[0m", currentDate.Day())
-				heatGrid.shade = theme.InvalidDayValue
-			} else {
-				heatmap, ok := heatmaps[currentDate]
-				if ok {
-					shadeIndex := GetTheShade(heatmap)
-					heatGrid.rank = shadeIndex
-					colorCode, haveShade := theme.StatusValues[shadeIndex]
-					if !haveShade {
-						colorCode = theme.ZeroCompletedHabitValue
-					}
-					heatGrid.shade = colorCode
-					heatGrid.haveInfo = true
-					heatGrid.totalNumberOfHabits = heatmap.TotalNumberOfHabits
-					heatGrid.completedHabits = heatmap.CompletedHabits
-				} else {
-					heatGrid.shade = theme.NoHabitsValue
+			if err := gui.HabitService.Rename(context.Background(), ids, title); err != nil {
+				if app.ErrorCode(err) != app.ECONFLICT {
+					return err
 				}
+				gui.showStatusMessage(app.ErrorMessage(err))
+				return nil
+			}
+			gui.rangeStart = time.Time{}
+			gui.showStatusMessage(fmt.Sprintf("Renamed %s on %d days", oldTitle, len(ids)))
+			if err := gui.HabitsPanel.CloseHabitPanel(); err != nil {
+				return err
 			}
-			grid[row][col] = heatGrid
-			currentDate = currentDate.AddDate(0, 0, 1) // move to the next day
+			return gui.renderHeatmap()
 		}
+		return gui.ChainPanel.openHabitPanel(oldTitle, fmt.Sprintf("Rename %s on %d days", oldTitle, len(ids)), onRename)
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	return gui.ChainPanel.openHabitPanel(suggestion, fmt.Sprintf("Habit to rename on %d days", days), onConfirm)
+}
+
+// the first habit of the day is suggested for the actions of the selected days
+func (gui *Gui) suggestHabit(day time.Time) (string, error) {
+	chain, err := gui.HabitService.GetAllByDay(context.Background(), day)
+	if err != nil {
+		return "", err
 	}
+	if len(chain.Habits) == 0 {
+		return "", nil
+	}
+	return chain.Habits[0].Title.String(), nil
+}
+
+// Init grid for the default view
+func (gui *Gui) initializeGrid() {
+	weekStart := gui.Config.Gui.GetWeekStart()
+	from, to := heatmap.LastYearRange(utils.Today(), weekStart)
+
+	gui.heatmapFirstDate = to
+	gui.heatmapLastDate = from
+
+	heatmaps, _, err := gui.HabitService.HeatMap(context.Background(), from, to)
+	if err != nil {
+		panic(err)
+	}
+	gui.streak = models.CalculateStreak(heatmaps, from, utils.Today())
+	gui.fillGrid(heatmap.NewGrid(from, to, weekStart, heatmaps, utils.Today()))
 }
 
 // Init grid selected year
 func (gui *Gui) initFromTo() {
-	// from=2023-01-01&to=2023-12-31
 	selectedYear, _ := strconv.Atoi(gui.YearsSelectList.GetSelected().option)
-	from := utils.CreateDate(selectedYear, 1, 1)
-	to := utils.CreateDate(selectedYear, 12, 31)
+	from, to := heatmap.YearRange(selectedYear)
 
 	gui.heatmapFirstDate = to
 	gui.heatmapLastDate = from
 
-	// Create the grid
-	grid = make([][]*HeatGrid, 7)
-	for i := range grid {
-		grid[i] = make([]*HeatGrid, 53)
-	}
-
-	defaultTheme := gui.Config.Gui.Theme.Selected
-	theme := gui.Config.Gui.Theme.ColorSchemes[defaultTheme]
 	heatmaps, _, err := gui.HabitService.HeatMap(context.Background(), from, to)
 	if err != nil {
 		panic(err)
 	}
+	gui.streak = models.CalculateStreak(heatmaps, from, lo.Ternary(to.After(utils.Today()), utils.Today(), to))
+	// The first and the last weeks of the year can be partial, a year spans 54 weeks at most
+	gui.fillGrid(heatmap.NewGrid(from, to, gui.Config.Gui.GetWeekStart(), heatmaps, utils.Today()))
+}
 
-	// Fill the grid with colored boxes
-	startWeekday := int(from.Weekday())
-	now := time.Now()
-	today := utils.CreateDate(now.Year(), now.Month(), now.Day())
-	currentDate := from
-	for col := 0; col < 53; col++ {
-		for row := 0; row < 7; row++ {
-			heatGrid := &HeatGrid{
-				row:    row,
-				column: col,
-				key:    currentDate,
-			}
-			if (col == 0 && startWeekday > row) || currentDate.Year() != from.Year() {
-				heatGrid.shade = theme.InvalidDayValue // not belongs to year, the january starts from the wednesday
-				heatGrid.key = time.Time{}
-			} else {
-				if currentDate.After(today) {
-					heatGrid.shade = theme.InvalidDayValue // the now is 2024 july 8, after this time all of them are null
-				} else {
-					heatmap, ok := heatmaps[currentDate]
-					if ok {
-						shadeIndex := GetTheShade(heatmap)
-						heatGrid.rank = shadeIndex
-						colorCode, haveShade := theme.StatusValues[shadeIndex]
-						if !haveShade {
-							colorCode = theme.ZeroCompletedHabitValue
-						}
-						heatGrid.shade = colorCode
-						heatGrid.haveInfo = true
-						heatGrid.totalNumberOfHabits = heatmap.TotalNumberOfHabits
-						heatGrid.completedHabits = heatmap.CompletedHabits
-					} else {
-						heatGrid.shade = theme.NoHabitsValue
-					}
-				}
-				currentDate = currentDate.AddDate(0, 0, 1) // move to the next day
-			}
-			grid[row][col] = heatGrid
+// Fills the grid with the colored boxes of the days
+func (gui *Gui) fillGrid(heatmapGrid *heatmap.Grid) {
+	theme := gui.GetColorScheme()
+	gui.monthLabels = heatmapGrid.MonthLabels()
+	grid = make([][]*HeatGrid, 7)
+	for row, cells := range heatmapGrid.Cells {
+		grid[row] = make([]*HeatGrid, len(cells))
+		for column, cell := range cells {
+			grid[row][column] = newHeatGrid(cell, theme)
 		}
 	}
 }
@@ -415,18 +525,53 @@ func (gui *Gui) reInitGrid(selected string) error {
 	return nil
 }
 
-func GetTheShade(heatmap *models.HeatMap) int {
-	if heatmap.CompletedHabits == 0 {
-		return 0
+// Returns the locale of the configured language
+func (gui *Gui) GetLocale() utils.Locale {
+	locale, _ := utils.GetLocale(gui.Config.Gui.Language)
+	return locale
+}
+
+// pads or cuts the label to the given width so the grid stays aligned
+func padLabel(label string, width int) string {
+	runes := []rune(label)
+	if len(runes) > width {
+		runes = runes[:width]
 	}
+	return string(runes) + strings.Repeat(" ", width-len(runes))
+}
 
-	completionRatio := float64(heatmap.CompletedHabits) / float64(heatmap.TotalNumberOfHabits)
-	shade := int(math.Round(completionRatio * 5))
+// Returns the selected color scheme converted for the color support of the terminal
+func (gui *Gui) GetColorScheme() config.HeatmapColorScheme {
+	selected := gui.Config.Gui.Theme.Selected
+	return gui.Config.Gui.Theme.ColorSchemes[selected].Resolve(gui.colorProfile)
+}
 
-	if shade < 1 {
-		shade = 1
+// Moves the heat map cursor to the day, the year of the day is shown when the
+// day is not in the heat map
+func (gui *Gui) jumpToDay(day time.Time) error {
+	if !moveCursorToDay(day) {
+		if !gui.YearsSelectList.SelectOption(strconv.Itoa(day.Year())) {
+			return nil
+		}
+		if err := gui.reInitGrid(gui.YearsSelectList.GetSelected().option); err != nil {
+			return err
+		}
+		moveCursorToDay(day)
+	}
+	return gui.renderHeatmap()
+}
+
+func moveCursorToDay(day time.Time) bool {
+	for _, row := range grid {
+		for _, slot := range row {
+			if !slot.key.IsZero() && slot.key.Equal(day) {
+				cursorX = slot.column
+				cursorY = slot.row
+				return true
+			}
+		}
 	}
-	return shade
+	return false
 }
 
 func (gui *Gui) GetDateFromHeatmapCursor() time.Time {
@@ -0,0 +1,36 @@
+package gui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/heatmap"
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMonthLabels(t *testing.T) {
+	locale, _ := utils.GetLocale("en")
+
+	t.Run("Given year should show the months above their first weeks", func(t *testing.T) {
+		from, to := heatmap.YearRange(2024)
+		grid := heatmap.NewGrid(from, to, time.Sunday, map[time.Time]*models.HeatMap{}, to)
+
+		line := formatMonthLabels(grid.MonthLabels(), grid.Columns(), locale)
+
+		assert.Equal(t, "     Jan     Feb", line[:16])
+		// December 1st is in the 48th column
+		assert.Equal(t, "Dec", line[5+48*2:5+48*2+3])
+	})
+
+	t.Run("Given last year should start with the month of the first day", func(t *testing.T) {
+		from, to := heatmap.LastYearRange(utils.CreateDate(2024, 7, 10), time.Sunday)
+		grid := heatmap.NewGrid(from, to, time.Sunday, map[time.Time]*models.HeatMap{}, to)
+
+		line := formatMonthLabels(grid.MonthLabels(), grid.Columns(), locale)
+
+		assert.Equal(t, locale.MonthName(from.Month())[:3], line[5:8])
+		assert.LessOrEqual(t, len(line), 5+grid.Columns()*2)
+	})
+}
@@ -4,7 +4,6 @@ import (
 	"strings"
 
 	"github.com/jesseduffield/gocui"
-	"github.com/metagunner/habheat/pkg/config"
 )
 
 type HabitPanelContext struct {
@@ -18,6 +17,8 @@ type HabitPanelViewModel struct {
 	id        int
 	title     string
 	onConfirm func(string) error
+	// the view that was focused when the panel is opened
+	previousView string
 }
 
 func NewHabitPanelContext(v *gocui.View, gui *Gui) *HabitPanelContext {
@@ -27,11 +28,16 @@ func NewHabitPanelContext(v *gocui.View, gui *Gui) *HabitPanelContext {
 		viewModel: viewModel,
 		gui:       gui,
 	}
-	gui.g.SetKeybinding(v.Name(), config.GetKey(gui.Config.Keybinding.Universal.Confirm), gocui.ModNone, gui.wrappedHandler(habitPanelContext.OnConfirm))
-	gui.g.SetKeybinding(v.Name(), config.GetKey(gui.Config.Keybinding.Universal.Close), gocui.ModNone, gui.wrappedHandler(habitPanelContext.CloseHabitPanel))
 	return habitPanelContext
 }
 
+func (self *HabitPanelContext) actionHandlers() actionHandlers {
+	return actionHandlers{
+		"confirm": self.OnConfirm,
+		"close":   self.CloseHabitPanel,
+	}
+}
+
 func (self *HabitPanelContext) OnConfirm() error {
 	title := self.GetHabitTitle()
 	return self.viewModel.onConfirm(title)
@@ -46,6 +52,9 @@ func (self *HabitPanelContext) SetPanelState(
 	self.viewModel.id = id
 	self.viewModel.title = title
 	self.viewModel.onConfirm = onConfirm
+	if currentView := self.gui.g.CurrentView(); currentView != nil && currentView.Name() != self.view.Name() {
+		self.viewModel.previousView = currentView.Name()
+	}
 
 	self.gui.g.Cursor = true
 	self.view.Title = habitInputTitle
@@ -63,11 +72,13 @@ func (self *HabitPanelContext) CloseHabitPanel() error {
 	self.view.Clear()
 	self.view.Visible = false
 	self.gui.g.Cursor = false
-	if _, err := self.gui.g.SetCurrentView(self.gui.ChainPanel.view.Name()); err != nil {
+	if _, err := self.gui.g.SetCurrentView(self.viewModel.previousView); err != nil {
 		return err
 	}
-	self.gui.ChainPanel.viewModel.list.RefreshOptions()
-	self.gui.ChainPanel.viewModel.list.Render()
+	if self.gui.ChainPanel.view.Visible {
+		self.gui.ChainPanel.viewModel.list.RefreshOptions()
+		self.gui.ChainPanel.viewModel.list.Render()
+	}
 
 	return nil
 }
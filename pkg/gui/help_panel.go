@@ -0,0 +1,85 @@
+package gui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/metagunner/habheat/pkg/config"
+	"github.com/samber/lo"
+)
+
+type HelpPanelContext struct {
+	view      *gocui.View
+	viewModel *HelpPanelViewModel
+	gui       *Gui
+}
+
+type HelpPanelViewModel struct {
+	list *SelectList
+	// the view that was focused when the help is opened
+	previousView string
+}
+
+func NewHelpPanelContext(v *gocui.View, gui *Gui) *HelpPanelContext {
+	viewModel := &HelpPanelViewModel{}
+	helpPanelContext := &HelpPanelContext{
+		view:      v,
+		viewModel: viewModel,
+		gui:       gui,
+	}
+
+	getDisplayStrings := func() []SelectItem {
+		result := []SelectItem{}
+		for _, group := range gui.GetActiveBindings(viewModel.previousView) {
+			if len(result) > 0 {
+				result = append(result, SelectItem{option: ""})
+			}
+			result = append(result, SelectItem{option: group.Title})
+			for _, binding := range group.Bindings {
+				result = append(result, SelectItem{option: fmt.Sprintf("  %-12s %s", config.GetKeyLabel(binding.Key), binding.Description)})
+			}
+		}
+		return result
+	}
+	viewModel.list = NewSelectList(gui, v, getDisplayStrings)
+
+	return helpPanelContext
+}
+
+func (self *HelpPanelContext) actionHandlers() actionHandlers {
+	return mergeActionHandlers(self.viewModel.list.actionHandlers(), actionHandlers{
+		"close": self.CloseHelpPanel,
+	})
+}
+
+func (self *HelpPanelContext) OpenHelpPanel() error {
+	// the title of the view the help is opened from
+	if currentView := self.gui.g.CurrentView(); currentView != nil && currentView.Name() != self.view.Name() {
+		self.view.Subtitle = lo.Ternary(currentView.Title != "", currentView.Title, currentView.Name())
+	}
+	return self.gui.openPopup(self.view, self.viewModel.list, &self.viewModel.previousView, self.CloseHelpPanel)
+}
+
+func (self *HelpPanelContext) CloseHelpPanel() error {
+	return self.gui.closePopup(self.view, self.viewModel.previousView)
+}
+
+// Returns the keybindings of the given view followed by the global ones
+func (gui *Gui) GetActiveBindings(viewName string) []config.ViewBindings {
+	viewBindings := gui.Config.Keybinding.GetViewBindings()
+	result := lo.Filter(viewBindings, func(group config.ViewBindings, _ int) bool {
+		return group.View == viewName
+	})
+	global, _ := lo.Find(viewBindings, func(group config.ViewBindings) bool {
+		return group.View == ""
+	})
+	// the digits are the counts of the motions in these views
+	if slices.Contains(countViews, viewName) {
+		global.Bindings = lo.Filter(global.Bindings, func(binding config.Binding, _ int) bool {
+			return len(binding.Key) != 1 || binding.Key[0] < '0' || binding.Key[0] > '9'
+		})
+	}
+
+	return append(result, global)
+}
@@ -0,0 +1,199 @@
+package gui
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/metagunner/habheat/pkg/config"
+	"github.com/metagunner/habheat/pkg/utils"
+)
+
+// views where a count can be typed before an action, e.g. 4l
+var countViews = []string{"heatmap"}
+
+// the largest count, the longer counts are cut
+const maxCount = 9999
+
+// the time the digit of a global action waits for the count to go on, e.g. 1
+// focuses the years unless a digit or a motion is typed in the time
+const countActionTimeout = 500 * time.Millisecond
+
+// Adds the digit to the count when the key is a digit typed for a count. A
+// count can't start with 0 or a digit bound to an action of the view, e.g. 0
+// moves to the start of the row, but they continue a typed count. The digits
+// of the global actions, e.g. 1 focuses the years, start a count too, their
+// action is kept as the count action until a key follows them.
+func (gui *Gui) typeCount(viewName string, key config.Key) bool {
+	r, ok := key.Key.(rune)
+	if !ok || key.Mod != gocui.ModNone || r < '0' || r > '9' {
+		return false
+	}
+	if !slices.Contains(countViews, viewName) || len(gui.pendingKeys) > 0 {
+		return false
+	}
+	if gui.count == 0 && (r == '0' || gui.isViewKey(viewName, key)) {
+		return false
+	}
+	if gui.count == 0 {
+		gui.countAction, _ = gui.findKeybinding(viewName, []config.Key{key})
+	}
+
+	gui.count = min(gui.count*10+int(r-'0'), maxCount)
+	gui.pendingView = viewName
+	return true
+}
+
+// Runs the action of the digit that started the count when no key followed
+// the digit in the countActionTimeout
+func (gui *Gui) startCountAction() {
+	action := gui.countAction
+	gui.countActionTimer = time.AfterFunc(countActionTimeout, func() {
+		gui.g.Update(func(*gocui.Gui) error {
+			return gui.runCountAction(action)
+		})
+	})
+}
+
+// Runs the action unless a key was typed after it became the count action
+func (gui *Gui) runCountAction(action *keybinding) error {
+	if action == nil || gui.countAction != action {
+		return nil
+	}
+	gui.countAction = nil
+	gui.count = 0
+	return action.action.Handler()
+}
+
+// Forgets the count action, a key follows its digit
+func (gui *Gui) stopCountAction() {
+	if gui.countActionTimer != nil {
+		gui.countActionTimer.Stop()
+	}
+	gui.countAction = nil
+}
+
+// Returns true when a keybinding of the view starts with the key
+func (gui *Gui) isViewKey(viewName string, key config.Key) bool {
+	for _, binding := range gui.keybindings[viewName] {
+		if binding.keys[0] == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the typed count or 1 when no count is typed, a count is used once
+func (gui *Gui) takeCount() int {
+	count := max(gui.count, 1)
+	gui.count = 0
+	return count
+}
+
+// Returns the typed count and keys as they are shown in the status, e.g. 4g
+func (gui *Gui) getPendingText() string {
+	text := ""
+	if gui.count > 0 {
+		text = strconv.Itoa(gui.count)
+	}
+	return text + config.GetSequenceLabel(gui.pendingKeys)
+}
+
+// Moves the cursor by the rows and the columns times the count, the cursor
+// stays in the grid
+func (gui *Gui) moveCursor(dy, dx int) error {
+	count := gui.takeCount()
+	cursorX, cursorY = getMovedCell(cursorX, cursorY, dy*count, dx*count)
+	return gui.renderHeatmap()
+}
+
+// Moves the cursor to the first day of the following weeks, or of the previous
+// weeks when the offset is negative
+func (gui *Gui) moveWeek(offset int) error {
+	cursorX, cursorY = getWeekCell(cursorX, cursorY, offset*gui.takeCount())
+	return gui.renderHeatmap()
+}
+
+// Moves the cursor to the first or the last day of the row
+func (gui *Gui) moveToRowEdge(end bool) error {
+	gui.takeCount()
+	column, ok := getRowEdge(cursorY, end)
+	if !ok {
+		return nil
+	}
+	cursorX = column
+	return gui.renderHeatmap()
+}
+
+// Returns the cell moved by the rows and the columns, the cell stays in the grid
+func getMovedCell(x, y, dy, dx int) (int, int) {
+	x = min(max(x+dx, 0), len(grid[0])-1)
+	y = min(max(y+dy, 0), len(grid)-1)
+	return x, y
+}
+
+// Returns the first day of the week moved by the weeks. Moving back from the
+// middle of a week moves to the first day of the week first, like b of vim.
+func getWeekCell(x, y, weeks int) (int, int) {
+	column := x + weeks
+	if weeks < 0 && y > firstDayOfColumn(x) {
+		column++
+	}
+	column = min(max(column, 0), len(grid[0])-1)
+	return column, firstDayOfColumn(column)
+}
+
+// Returns the column of the first or the last day of the row, false when the
+// row doesn't have a day
+func getRowEdge(row int, end bool) (int, bool) {
+	columns := make([]int, 0, len(grid[row]))
+	for column, slot := range grid[row] {
+		if !slot.key.IsZero() {
+			columns = append(columns, column)
+		}
+	}
+	if len(columns) == 0 {
+		return 0, false
+	}
+	if end {
+		return columns[len(columns)-1], true
+	}
+	return columns[0], true
+}
+
+// Moves the cursor to the first day of the heat map or to the last day until today
+func (gui *Gui) moveToEdgeDay(last bool) error {
+	gui.takeCount()
+	today := utils.Today()
+	var target *HeatGrid
+	for column := range grid[0] {
+		for row := range grid {
+			slot := grid[row][column]
+			if slot.key.IsZero() || slot.key.After(today) {
+				continue
+			}
+			if target == nil || last {
+				target = slot
+			}
+		}
+	}
+	if target == nil {
+		return nil
+	}
+
+	cursorX = target.column
+	cursorY = target.row
+	return gui.renderHeatmap()
+}
+
+// Returns the row of the first day in the column, the first week of a year can
+// start in the middle of the column
+func firstDayOfColumn(column int) int {
+	for row := range grid {
+		if !grid[row][column].key.IsZero() {
+			return row
+		}
+	}
+	return 0
+}
@@ -0,0 +1,175 @@
+package gui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/config"
+	"github.com/metagunner/habheat/pkg/heatmap"
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// fills the grid with 2024, the weeks start on Sunday. January 1st is the
+// Monday of the first column and December 31st the Tuesday of the last one.
+func setupTestGrid() {
+	from, to := heatmap.YearRange(2024)
+	gui := &Gui{Config: config.GetDefaultConfig()}
+	gui.fillGrid(heatmap.NewGrid(from, to, time.Sunday, map[time.Time]*models.HeatMap{}, utils.CreateDate(2024, 12, 31)))
+}
+
+func TestTypeCount(t *testing.T) {
+	parseKey := func(label string) config.Key {
+		keys, err := config.ParseKeys(label)
+		assert.NoError(t, err)
+		return keys[0][0]
+	}
+	newGui := func() *Gui {
+		return &Gui{keybindings: map[string][]keybinding{
+			"":        {{keys: []config.Key{parseKey("1")}}, {keys: []config.Key{parseKey("2")}}},
+			"heatmap": {{keys: []config.Key{parseKey("0")}}, {keys: []config.Key{parseKey("g"), parseKey("g")}}},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		view     string
+		keys     []string
+		typed    []bool
+		expected int
+	}{
+		{name: "Given digit should start the count", view: "heatmap", keys: []string{"4"}, typed: []bool{true}, expected: 4},
+		{name: "Given digits of global actions should be a count", view: "heatmap", keys: []string{"1", "2"}, typed: []bool{true, true}, expected: 12},
+		{name: "Given 0 should not start the count", view: "heatmap", keys: []string{"0"}, typed: []bool{false}, expected: 0},
+		{name: "Given 0 after a digit should continue the count", view: "heatmap", keys: []string{"2", "0"}, typed: []bool{true, true}, expected: 20},
+		{name: "Given key that is not a digit should not be a count", view: "heatmap", keys: []string{"g"}, typed: []bool{false}, expected: 0},
+		{name: "Given digit in a view without counts should not be a count", view: "years", keys: []string{"1"}, typed: []bool{false}, expected: 0},
+		{name: "Given long count should be cut", view: "heatmap", keys: []string{"9", "9", "9", "9", "9"}, typed: []bool{true, true, true, true, true}, expected: maxCount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gui := newGui()
+
+			typed := []bool{}
+			for _, key := range tt.keys {
+				typed = append(typed, gui.typeCount(tt.view, parseKey(key)))
+			}
+
+			assert.Equal(t, tt.typed, typed)
+			assert.Equal(t, tt.expected, gui.count)
+		})
+	}
+
+	t.Run("Given pending keys should not be a count", func(t *testing.T) {
+		gui := newGui()
+		gui.pendingKeys = []config.Key{parseKey("g")}
+
+		assert.False(t, gui.typeCount("heatmap", parseKey("4")))
+	})
+
+	t.Run("Given digit of a global action alone should run the action", func(t *testing.T) {
+		gui := newGui()
+		focused := false
+		gui.keybindings[""][0].action.Handler = func() error {
+			focused = true
+			return nil
+		}
+		gui.typeCount("heatmap", parseKey("1"))
+		action := gui.countAction
+
+		assert.NoError(t, gui.runCountAction(action))
+		assert.True(t, focused)
+		assert.Equal(t, 0, gui.count)
+	})
+
+	t.Run("Given digit of a global action followed by a key should not run the action", func(t *testing.T) {
+		gui := newGui()
+		gui.keybindings[""][0].action.Handler = func() error {
+			t.Fatal("the action should not run")
+			return nil
+		}
+		gui.typeCount("heatmap", parseKey("1"))
+		action := gui.countAction
+		gui.stopCountAction()
+		gui.typeCount("heatmap", parseKey("2"))
+
+		assert.NoError(t, gui.runCountAction(action))
+		assert.Equal(t, 12, gui.count)
+	})
+
+	t.Run("Given count should be taken once", func(t *testing.T) {
+		gui := newGui()
+		gui.typeCount("heatmap", parseKey("3"))
+
+		assert.Equal(t, 3, gui.takeCount())
+		assert.Equal(t, 1, gui.takeCount())
+	})
+}
+
+func TestGetMovedCell(t *testing.T) {
+	setupTestGrid()
+	tests := []struct {
+		name                 string
+		x, y, dy, dx         int
+		expectedX, expectedY int
+	}{
+		{name: "Given move should add the rows and the columns", x: 3, y: 3, dy: 2, dx: 4, expectedX: 7, expectedY: 5},
+		{name: "Given move past the last column should stop at it", x: 3, y: 3, dx: 100, expectedX: 52, expectedY: 3},
+		{name: "Given move past the first row should stop at it", x: 3, y: 3, dy: -10, expectedX: 3, expectedY: 0},
+		{name: "Given move past the last row should stop at it", x: 3, y: 3, dy: 10, expectedX: 3, expectedY: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := getMovedCell(tt.x, tt.y, tt.dy, tt.dx)
+
+			assert.Equal(t, []int{tt.expectedX, tt.expectedY}, []int{x, y})
+		})
+	}
+}
+
+func TestGetWeekCell(t *testing.T) {
+	setupTestGrid()
+	tests := []struct {
+		name                 string
+		x, y, weeks          int
+		expectedX, expectedY int
+	}{
+		{name: "Given next week should move to its Sunday", x: 0, y: 3, weeks: 1, expectedX: 1, expectedY: 0},
+		{name: "Given weeks should move to the Sunday of the last one", x: 1, y: 3, weeks: 4, expectedX: 5, expectedY: 0},
+		{name: "Given previous week in the middle of a week should move to its Sunday", x: 5, y: 3, weeks: -1, expectedX: 5, expectedY: 0},
+		{name: "Given previous week on a Sunday should move to the previous Sunday", x: 5, y: 0, weeks: -1, expectedX: 4, expectedY: 0},
+		{name: "Given first week should move to its first day", x: 1, y: 0, weeks: -1, expectedX: 0, expectedY: 1},
+		{name: "Given weeks past the last one should stop at it", x: 0, y: 1, weeks: 100, expectedX: 52, expectedY: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := getWeekCell(tt.x, tt.y, tt.weeks)
+
+			assert.Equal(t, []int{tt.expectedX, tt.expectedY}, []int{x, y})
+		})
+	}
+}
+
+func TestGetRowEdge(t *testing.T) {
+	setupTestGrid()
+	tests := []struct {
+		name     string
+		row      int
+		end      bool
+		expected int
+	}{
+		{name: "Given Sundays should start with the first Sunday", row: 0, expected: 1},
+		{name: "Given Sundays should end with the last Sunday", row: 0, end: true, expected: 52},
+		{name: "Given Wednesdays should end before the last column", row: 3, end: true, expected: 51},
+		{name: "Given Mondays should start with the first column", row: 1, expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			column, ok := getRowEdge(tt.row, tt.end)
+
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, column)
+		})
+	}
+}
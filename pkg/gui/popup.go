@@ -0,0 +1,38 @@
+package gui
+
+import "github.com/jesseduffield/gocui"
+
+// Opens the list view on top of the focused view and keeps the name of the
+// focused view to return to it. The key that opens the popup closes it as well.
+func (gui *Gui) openPopup(view *gocui.View, list *SelectList, previousView *string, closePopup func() error) error {
+	currentView := gui.g.CurrentView()
+	if currentView == nil {
+		return nil
+	}
+	if currentView.Name() == view.Name() {
+		return closePopup()
+	}
+
+	*previousView = currentView.Name()
+	view.Clear()
+	view.Visible = true
+	list.Reset()
+	list.RefreshOptions()
+	list.Render()
+
+	if _, err := gui.g.SetViewOnTop(view.Name()); err != nil {
+		return err
+	}
+	if _, err := gui.g.SetCurrentView(view.Name()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Hides the popup and focuses the view it is opened from
+func (gui *Gui) closePopup(view *gocui.View, previousView string) error {
+	view.Clear()
+	view.Visible = false
+	_, err := gui.g.SetCurrentView(previousView)
+	return err
+}
@@ -0,0 +1,153 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/metagunner/habheat/pkg/models"
+)
+
+// number of the days listed for a search
+const searchLimit = 200
+
+type SearchPanelContext struct {
+	view         *gocui.View
+	viewModel    *SearchPanelViewModel
+	habitService models.HabitService
+	gui          *Gui
+}
+
+type SearchPanelViewModel struct {
+	list  *SelectList
+	query string
+	// days of the listed habits by their ids
+	days map[int]time.Time
+}
+
+func NewSearchPanelContext(v *gocui.View, gui *Gui, habitService models.HabitService) *SearchPanelContext {
+	viewModel := &SearchPanelViewModel{days: map[int]time.Time{}}
+	getDisplayStrings := func() []SelectItem {
+		result := []SelectItem{}
+		if viewModel.query == "" {
+			return result
+		}
+		habits, err := habitService.Search(context.Background(), viewModel.query, searchLimit)
+		if err != nil {
+			return result
+		}
+
+		viewModel.days = make(map[int]time.Time, len(habits))
+		for _, habit := range habits {
+			status := " "
+			if habit.IsCompleted {
+				status = "X"
+			} else if habit.IsSkipped {
+				status = "-"
+			}
+			option := fmt.Sprintf("%s  [%s] %s", habit.Day.Format(time.DateOnly), status, habit.Title)
+			if note, _, _ := strings.Cut(habit.Note, "\n"); note != "" {
+				option += "  " + note
+			}
+			viewModel.days[int(habit.Id)] = habit.Day
+			result = append(result, SelectItem{id: int(habit.Id), option: option})
+		}
+		return result
+	}
+	viewModel.list = NewSelectList(gui, v, getDisplayStrings)
+
+	return &SearchPanelContext{
+		view:         v,
+		viewModel:    viewModel,
+		habitService: habitService,
+		gui:          gui,
+	}
+}
+
+func (self *SearchPanelContext) actionHandlers() actionHandlers {
+	return mergeActionHandlers(self.viewModel.list.actionHandlers(), actionHandlers{
+		"select": self.ShowDay,
+		"close":  self.CloseSearchPanel,
+	})
+}
+
+// Asks for the search query, the matching days are listed in the search panel
+func (self *SearchPanelContext) OpenSearch() error {
+	currentView := self.gui.g.CurrentView()
+	if currentView == nil || currentView.Name() == self.gui.HabitsPanel.view.Name() {
+		return nil
+	}
+	if currentView.Name() == self.view.Name() {
+		self.view.Visible = false
+		if _, err := self.gui.g.SetCurrentView(self.gui.ViewHeatmap.Name()); err != nil {
+			return err
+		}
+	}
+
+	onConfirm := func(query string) error {
+		if query == "" {
+			return nil
+		}
+		if err := self.gui.HabitsPanel.CloseHabitPanel(); err != nil {
+			return err
+		}
+		return self.openSearchPanel(query)
+	}
+	self.gui.HabitsPanel.SetPanelState(0, self.viewModel.query, "Search", onConfirm)
+	viewName := self.gui.HabitsPanel.view.Name()
+	if _, err := self.gui.g.SetViewOnTop(viewName); err != nil {
+		return err
+	}
+	if _, err := self.gui.g.SetCurrentView(viewName); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (self *SearchPanelContext) openSearchPanel(query string) error {
+	self.viewModel.query = query
+	self.view.Title = fmt.Sprintf("Search %q", query)
+	self.viewModel.list.SetEmptyMessage(fmt.Sprintf("No habits match %q", query))
+	self.view.Clear()
+	self.view.Visible = true
+	self.viewModel.list.Reset()
+	self.viewModel.list.RefreshOptions()
+	self.viewModel.list.Render()
+
+	viewName := self.view.Name()
+	if _, err := self.gui.g.SetViewOnTop(viewName); err != nil {
+		return err
+	}
+	if _, err := self.gui.g.SetCurrentView(viewName); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (self *SearchPanelContext) CloseSearchPanel() error {
+	self.view.Clear()
+	self.view.Visible = false
+	_, err := self.gui.g.SetCurrentView(self.gui.ViewHeatmap.Name())
+	return err
+}
+
+// Closes the search and moves the heat map cursor to the day of the selected habit
+func (self *SearchPanelContext) ShowDay() error {
+	selected := self.viewModel.list.GetSelected()
+	if selected == (SelectItem{}) {
+		return nil
+	}
+
+	if self.gui.ChainPanel.view.Visible {
+		self.gui.ChainPanel.view.Clear()
+		self.gui.ChainPanel.view.Visible = false
+	}
+	if err := self.CloseSearchPanel(); err != nil {
+		return err
+	}
+	return self.gui.jumpToDay(self.viewModel.days[selected.id])
+}
@@ -2,9 +2,9 @@ package gui
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/jesseduffield/gocui"
-	"github.com/metagunner/habheat/pkg/config"
 )
 
 type SelectList struct {
@@ -16,6 +16,8 @@ type SelectList struct {
 	getDisplayStrings func() []SelectItem
 	emptyMessage      string
 	isRendered        bool
+	// index of the item the selected range starts from, -1 when no range is selected
+	rangeStart int
 }
 
 type SelectItem struct {
@@ -24,23 +26,15 @@ type SelectItem struct {
 }
 
 func NewSelectList(g *Gui, view *gocui.View, getDisplayStrings func() []SelectItem) *SelectList {
-	s := &SelectList{gui: g, view: view, getDisplayStrings: getDisplayStrings}
+	s := &SelectList{gui: g, view: view, getDisplayStrings: getDisplayStrings, rangeStart: -1}
+	return s
+}
 
-	// handlers
-	if err := s.gui.g.SetKeybinding(s.view.Name(), config.GetKey(g.Config.Keybinding.Universal.NextItem), gocui.ModNone, g.wrappedHandler(s.HandleNextLine)); err != nil {
-		panic(err)
-	}
-	if err := s.gui.g.SetKeybinding(s.view.Name(), config.GetKey(g.Config.Keybinding.Universal.PrevItem), gocui.ModNone, g.wrappedHandler(s.HandlePrevLine)); err != nil {
-		panic(err)
-	}
-	if err := s.gui.g.SetKeybinding(s.view.Name(), config.GetKey(g.Config.Keybinding.Universal.NextItemAlt), gocui.ModNone, g.wrappedHandler(s.HandleNextLine)); err != nil {
-		panic(err)
-	}
-	if err := s.gui.g.SetKeybinding(s.view.Name(), config.GetKey(g.Config.Keybinding.Universal.PrevItemAlt), gocui.ModNone, g.wrappedHandler(s.HandlePrevLine)); err != nil {
-		panic(err)
+func (self *SelectList) actionHandlers() actionHandlers {
+	return actionHandlers{
+		"prevItem": self.HandlePrevLine,
+		"nextItem": self.HandleNextLine,
 	}
-
-	return s
 }
 
 func (self *SelectList) HandlePrevLine() error {
@@ -86,13 +80,81 @@ func (self *SelectList) GetSelected() SelectItem {
 	return self.items[self.selectedIndex]
 }
 
+// selects the item with the option, returns false when there is no such item
+func (self *SelectList) SelectOption(option string) bool {
+	index := slices.IndexFunc(self.items, func(x SelectItem) bool { return x.option == option })
+	if index == -1 {
+		return false
+	}
+
+	_, height := self.ViewPortYBounds()
+	origin := max(index-height, 0)
+	self.selectedIndex = index
+	self.cursorPos = index - origin
+	self.view.SetOrigin(0, origin)
+	self.Render()
+	return true
+}
+
+// Returns the items of the selected range, or the selected item when no range is selected
+func (self *SelectList) GetSelectedItems() []SelectItem {
+	if len(self.items) == 0 {
+		return []SelectItem{}
+	}
+	if !self.IsSelectingRange() {
+		return []SelectItem{self.items[self.selectedIndex]}
+	}
+	from := min(self.rangeStart, self.selectedIndex)
+	to := max(self.rangeStart, self.selectedIndex)
+	return slices.Clone(self.items[from : to+1])
+}
+
+// starts selecting a range from the selected item, or stops it
+func (self *SelectList) ToggleRangeSelect() error {
+	if self.IsSelectingRange() {
+		self.rangeStart = -1
+	} else if len(self.items) > 0 {
+		self.rangeStart = self.selectedIndex
+	}
+	self.Render()
+	return nil
+}
+
+func (self *SelectList) IsSelectingRange() bool {
+	return self.rangeStart >= 0
+}
+
+func (self *SelectList) CancelRangeSelect() {
+	self.rangeStart = -1
+}
+
 func (self *SelectList) SetEmptyMessage(message string) {
 	self.emptyMessage = message
 }
 
+// moves the selection back to the first item
+func (self *SelectList) Reset() {
+	self.selectedIndex = 0
+	self.cursorPos = 0
+	self.rangeStart = -1
+	self.view.SetOrigin(0, 0)
+}
+
 func (self *SelectList) RefreshOptions() {
 	items := self.getDisplayStrings()
 	self.items = items
+
+	// keep the selection in the list when the last items are removed
+	if last := max(len(items)-1, 0); self.selectedIndex > last {
+		self.cursorPos -= self.selectedIndex - last
+		self.selectedIndex = last
+		if self.cursorPos < 0 {
+			self.Reset()
+		}
+	}
+	if self.rangeStart >= len(items) {
+		self.rangeStart = len(items) - 1
+	}
 }
 
 func (self *SelectList) Render() {
@@ -106,7 +168,14 @@ func (self *SelectList) Render() {
 	}
 
 	self.view.Clear()
-	for _, item := range self.items {
+	from := min(self.rangeStart, self.selectedIndex)
+	to := max(self.rangeStart, self.selectedIndex)
+	for i, item := range self.items {
+		if self.IsSelectingRange() && i >= from && i <= to {
+			// the items of the selected range are shown in reverse video
+			self.view.WriteString("\x1b[7m" + item.option + "\x1b[0m\n")
+			continue
+		}
 		self.view.WriteString(item.option + "\n")
 	}
 	self.view.SetCursor(0, self.cursorPos)
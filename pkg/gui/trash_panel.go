@@ -0,0 +1,92 @@
+package gui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/metagunner/habheat/pkg/models"
+)
+
+type TrashPanelContext struct {
+	view         *gocui.View
+	viewModel    *TrashPanelViewModel
+	habitService models.HabitService
+	gui          *Gui
+}
+
+type TrashPanelViewModel struct {
+	list *SelectList
+	// the view that was focused when the trash is opened
+	previousView string
+}
+
+func NewTrashPanelContext(v *gocui.View, gui *Gui, habitService models.HabitService) *TrashPanelContext {
+	viewModel := &TrashPanelViewModel{}
+	getDisplayStrings := func() []SelectItem {
+		habits, err := habitService.Trash(context.Background())
+		if err != nil {
+			return []SelectItem{}
+		}
+
+		result := []SelectItem{}
+		for _, habit := range habits {
+			result = append(result, SelectItem{id: int(habit.Id), option: fmt.Sprintf("%s  %s", habit.Day.Format(time.DateOnly), habit.Title)})
+		}
+		return result
+	}
+	viewModel.list = NewSelectList(gui, v, getDisplayStrings)
+
+	return &TrashPanelContext{
+		view:         v,
+		viewModel:    viewModel,
+		habitService: habitService,
+		gui:          gui,
+	}
+}
+
+func (self *TrashPanelContext) actionHandlers() actionHandlers {
+	return mergeActionHandlers(self.viewModel.list.actionHandlers(), actionHandlers{
+		"restoreHabit": self.RestoreHabit,
+		"close":        self.CloseTrashPanel,
+	})
+}
+
+func (self *TrashPanelContext) OpenTrashPanel() error {
+	return self.gui.openPopup(self.view, self.viewModel.list, &self.viewModel.previousView, self.CloseTrashPanel)
+}
+
+func (self *TrashPanelContext) CloseTrashPanel() error {
+	if err := self.gui.closePopup(self.view, self.viewModel.previousView); err != nil {
+		return err
+	}
+
+	// the restored habits are shown again
+	if self.gui.ChainPanel.view.Visible {
+		self.gui.ChainPanel.view.Clear()
+		self.gui.ChainPanel.viewModel.list.RefreshOptions()
+		self.gui.ChainPanel.viewModel.list.Render()
+	}
+	self.gui.YearsSelectList.RefreshOptions()
+	self.gui.YearsSelectList.Render()
+	if err := self.gui.reInitGrid(self.gui.YearsSelectList.GetSelected().option); err != nil {
+		return err
+	}
+	return self.gui.renderHeatmap()
+}
+
+func (self *TrashPanelContext) RestoreHabit() error {
+	selected := self.viewModel.list.GetSelected()
+	if selected == (SelectItem{}) {
+		return nil
+	}
+
+	if err := self.habitService.Restore(context.Background(), models.HabitId(selected.id)); err != nil {
+		return err
+	}
+	self.view.Clear()
+	self.viewModel.list.RefreshOptions()
+	self.viewModel.list.Render()
+	return nil
+}
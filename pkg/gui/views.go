@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"fmt"
 	"strconv"
 	"time"
 
@@ -38,18 +37,18 @@ func (gui *Gui) createAllViews() error {
 	yearsV.InactiveViewSelBgColor = gocui.ColorDefault | gocui.AttrBold
 
 	getDisplayStrings := func() []SelectItem {
-		currentYear := time.Now().Year()
+		currentYear := utils.Today().Year()
 		var years []int
 		var dayStr string
 		var ts time.Time
-		err := gui.db.QueryRow(context.Background(), `SELECT day FROM habit ORDER BY day LIMIT 1`).Scan(&dayStr)
+		err := gui.db.QueryRow(context.Background(), `SELECT day FROM habit WHERE deleted_at IS NULL ORDER BY day LIMIT 1`).Scan(&dayStr)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return []SelectItem{{id: 0, option: strconv.Itoa(currentYear)}}
 			}
 		}
 
-		ts, _ = time.Parse(time.RFC3339, dayStr)
+		ts, _ = time.Parse(time.DateOnly, dayStr)
 		years = utils.GetYearsBetween(ts.Year(), currentYear)
 
 		return append([]SelectItem{{id: 0, option: "Default"}}, lo.Map(years, func(year int, _ int) SelectItem {
@@ -75,13 +74,7 @@ func (gui *Gui) createAllViews() error {
 	}
 	colorsV.Title = "Colors"
 	colorsV.FrameRunes = roundedFrameRunes
-	defaultTheme := gui.Config.Gui.Theme.Selected
-	color := gui.Config.Gui.Theme.ColorSchemes[defaultTheme]
-	fmt.Fprint(colorsV, "Less ")
-	for i := 1; i <= len(color.StatusValues); i++ {
-		fmt.Fprint(colorsV, color.StatusValues[i])
-	}
-	fmt.Fprint(colorsV, " More")
+	gui.renderLegend()
 
 	habitPanel, err := gui.g.SetView("habitpanel", maxX/2-30, maxY/2-2, maxX/2+30, maxY/2, 0)
 	if err != nil && !gocui.IsUnknownView(err) {
@@ -105,5 +98,62 @@ func (gui *Gui) createAllViews() error {
 	chainPanel.CanScrollPastBottom = true
 	chainPanel.Highlight = true
 
+	trashPanel, err := gui.g.SetView("trash", maxX/4, maxY/4, 3*maxX/4, 3*maxY/4, 0)
+	if err != nil && !gocui.IsUnknownView(err) {
+		return err
+	}
+	trashPanel.Title = "Trash"
+	trashPanel.FrameRunes = roundedFrameRunes
+	trashPanel.FgColor = gocui.ColorWhite
+	trashPanel.SelBgColor = gocui.ColorBlue
+	gui.TrashPanel = NewTrashPanelContext(trashPanel, gui, gui.HabitService)
+	trashPanel.Visible = false
+	trashPanel.Highlight = true
+
+	searchPanel, err := gui.g.SetView("search", maxX/4, maxY/4, 3*maxX/4, 3*maxY/4, 0)
+	if err != nil && !gocui.IsUnknownView(err) {
+		return err
+	}
+	searchPanel.Title = "Search"
+	searchPanel.FrameRunes = roundedFrameRunes
+	searchPanel.FgColor = gocui.ColorWhite
+	searchPanel.SelBgColor = gocui.ColorBlue
+	gui.SearchPanel = NewSearchPanelContext(searchPanel, gui, gui.HabitService)
+	searchPanel.Visible = false
+	searchPanel.Highlight = true
+
+	helpPanel, err := gui.g.SetView("help", maxX/2-30, maxY/4, maxX/2+30, 3*maxY/4, 0)
+	if err != nil && !gocui.IsUnknownView(err) {
+		return err
+	}
+	helpPanel.Title = "Keybindings"
+	helpPanel.FrameRunes = roundedFrameRunes
+	helpPanel.FgColor = gocui.ColorWhite
+	helpPanel.SelBgColor = gocui.ColorBlue
+	gui.HelpPanel = NewHelpPanelContext(helpPanel, gui)
+	helpPanel.Visible = false
+	helpPanel.Highlight = true
+
+	commandPalette, err := gui.g.SetView("palette", maxX/2-30, maxY/4, maxX/2+30, maxY/4+2, 0)
+	if err != nil && !gocui.IsUnknownView(err) {
+		return err
+	}
+	commandPalette.Title = "Command palette"
+	commandPalette.FrameRunes = roundedFrameRunes
+	commandPalette.Visible = false
+	commandPalette.Editable = true
+
+	paletteActions, err := gui.g.SetView("paletteactions", maxX/2-30, maxY/4+3, maxX/2+30, 3*maxY/4, 0)
+	if err != nil && !gocui.IsUnknownView(err) {
+		return err
+	}
+	paletteActions.Title = "Actions"
+	paletteActions.FrameRunes = roundedFrameRunes
+	paletteActions.FgColor = gocui.ColorWhite
+	paletteActions.SelBgColor = gocui.ColorBlue
+	gui.CommandPalette = NewCommandPaletteContext(commandPalette, paletteActions, gui)
+	paletteActions.Visible = false
+	paletteActions.Highlight = true
+
 	return nil
 }
@@ -0,0 +1,151 @@
+package heatmap
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/models"
+)
+
+// Stats are the totals of the days of the grid until today
+type Stats struct {
+	Streak models.Streak
+	// number of completed habits
+	Completions int
+}
+
+func (g *Grid) Stats() Stats {
+	days := g.Days()
+	if len(days) == 0 {
+		return Stats{}
+	}
+
+	var stats Stats
+	heatmaps := make(map[time.Time]*models.HeatMap, len(days))
+	for _, cell := range days {
+		if cell.HeatMap == nil {
+			continue
+		}
+		heatmaps[cell.Day] = cell.HeatMap
+		stats.Completions += cell.HeatMap.CompletedHabits
+	}
+	stats.Streak = models.CalculateStreak(heatmaps, days[0].Day, days[len(days)-1].Day)
+	return stats
+}
+
+const (
+	CardLight = "light"
+	CardDark  = "dark"
+
+	// the stats are below the heat map
+	CardLayoutStacked = "stacked"
+	// the stats are on the right of the heat map
+	CardLayoutSide = "side"
+)
+
+// CardOptions are the options of a card with the heat map and the stats
+type CardOptions struct {
+	Options
+	Title string
+	// CardLight or CardDark
+	Variant string
+	// CardLayoutStacked or CardLayoutSide
+	Layout string
+}
+
+// the colors of the card variants
+type cardColors struct {
+	background string
+	border     string
+	text       string
+	muted      string
+	empty      string
+}
+
+var cardVariants = map[string]cardColors{
+	CardLight: {background: "#ffffff", border: "#d0d7de", text: "#24292f", muted: "#57606a", empty: "#ebedf0"},
+	CardDark:  {background: "#0d1117", border: "#30363d", text: "#e6edf3", muted: "#8b949e", empty: "#161b22"},
+}
+
+func IsCardVariant(variant string) bool {
+	_, ok := cardVariants[variant]
+	return ok
+}
+
+func IsCardLayout(layout string) bool {
+	return layout == CardLayoutStacked || layout == CardLayoutSide
+}
+
+const (
+	cardPadding   = 16
+	cardTitleSize = 14
+	statValueSize = 20
+	statLabelSize = 11
+	// width of a stat in the stacked layout and of the stats column in the side layout
+	statWidth = 120
+	// height of a stat, the value and the label below it
+	statHeight = statValueSize + statLabelSize + 8
+)
+
+// WriteCard draws a self-contained SVG card with the title, the heat map, the
+// streaks and the completions, e.g. for a profile README
+func WriteCard(w io.Writer, grid *Grid, opts CardOptions) error {
+	colors, ok := cardVariants[opts.Variant]
+	if !ok {
+		colors = cardVariants[CardLight]
+	}
+	gridOpts := opts.Options
+	gridOpts.TextColor = colors.muted
+	gridOpts.EmptyColor = colors.empty
+	gridOpts.Background = ""
+
+	stats := grid.Stats()
+	statItems := []struct {
+		value string
+		label string
+	}{
+		{formatDays(stats.Streak.Current), "Current streak"},
+		{formatDays(stats.Streak.Longest), "Longest streak"},
+		{fmt.Sprint(stats.Completions), "Completions"},
+	}
+
+	gridWidth, gridHeight := gridOpts.Size(grid)
+	gridY := cardPadding + cardTitleSize + cardPadding/2
+	statsX, statsY := cardPadding, gridY+gridHeight+cardPadding
+	width, height := max(gridWidth, len(statItems)*statWidth)+2*cardPadding, statsY+statHeight+cardPadding
+	if opts.Layout == CardLayoutSide {
+		statsX, statsY = cardPadding+gridWidth+cardPadding, gridY
+		width = statsX + statWidth + cardPadding
+		height = max(gridY+gridHeight, statsY+len(statItems)*statHeight) + cardPadding
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(b, `<rect x="0.5" y="0.5" width="%d" height="%d" rx="6" fill="%s" stroke="%s"/>`+"\n", width-1, height-1, colors.background, colors.border)
+	fmt.Fprintf(b, `<text x="%d" y="%d" font-family="%s" font-size="%d" font-weight="bold" fill="%s">%s</text>`+"\n",
+		cardPadding, cardPadding+cardTitleSize-2, html.EscapeString(opts.FontFamily), cardTitleSize, colors.text, html.EscapeString(opts.Title))
+	writeSVGGrid(b, grid, gridOpts, cardPadding, gridY)
+
+	fmt.Fprintf(b, `<g font-family="%s">`+"\n", html.EscapeString(opts.FontFamily))
+	for i, item := range statItems {
+		x, y := statsX+i*statWidth, statsY
+		if opts.Layout == CardLayoutSide {
+			x, y = statsX, statsY+i*statHeight
+		}
+		fmt.Fprintf(b, `<text x="%d" y="%d" font-size="%d" font-weight="bold" fill="%s">%s</text>`+"\n", x, y+statValueSize, statValueSize, colors.text, item.value)
+		fmt.Fprintf(b, `<text x="%d" y="%d" font-size="%d" fill="%s">%s</text>`+"\n", x, y+statValueSize+statLabelSize+4, statLabelSize, colors.muted, item.label)
+	}
+	fmt.Fprintln(b, `</g>`)
+	fmt.Fprintln(b, `</svg>`)
+	return b.Flush()
+}
+
+func formatDays(days int) string {
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
@@ -0,0 +1,66 @@
+package heatmap
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrid_Stats(t *testing.T) {
+	from, to := YearRange(2024)
+	day := func(d int) time.Time { return utils.CreateDate(2024, 3, d) }
+	heatmaps := map[time.Time]*models.HeatMap{
+		day(1): {TotalNumberOfHabits: 2, CompletedHabits: 2},
+		day(2): {TotalNumberOfHabits: 2, CompletedHabits: 1},
+		day(3): {TotalNumberOfHabits: 2},
+		day(4): {TotalNumberOfHabits: 1, CompletedHabits: 1},
+		day(5): {Vacation: true},
+		day(6): {TotalNumberOfHabits: 1, CompletedHabits: 1},
+	}
+
+	t.Run("Given days until today should count the streaks and the completions", func(t *testing.T) {
+		grid := NewGrid(from, to, time.Sunday, heatmaps, day(6))
+
+		stats := grid.Stats()
+
+		assert.Equal(t, models.Streak{Current: 2, Longest: 2}, stats.Streak)
+		assert.Equal(t, 5, stats.Completions)
+	})
+	t.Run("Given future year should be empty", func(t *testing.T) {
+		grid := NewGrid(from, to, time.Sunday, heatmaps, utils.CreateDate(2023, 12, 31))
+
+		assert.Equal(t, Stats{}, grid.Stats())
+	})
+}
+
+func TestWriteCard(t *testing.T) {
+	from, to := YearRange(2024)
+	heatmaps := map[time.Time]*models.HeatMap{utils.CreateDate(2024, 3, 1): {TotalNumberOfHabits: 1, CompletedHabits: 1}}
+	grid := NewGrid(from, to, time.Sunday, heatmaps, to)
+	write := func(opts CardOptions) string {
+		var b strings.Builder
+		assert.NoError(t, WriteCard(&b, grid, opts))
+		return b.String()
+	}
+
+	t.Run("Given dark variant should draw the dark colors", func(t *testing.T) {
+		svg := write(CardOptions{Options: testOptions(), Title: "Habits & more", Variant: CardDark, Layout: CardLayoutStacked})
+
+		assert.Contains(t, svg, `fill="#0d1117"`)
+		assert.Contains(t, svg, `fill="#161b22" data-day="2024-01-01"`)
+		assert.Contains(t, svg, `>Habits &amp; more</text>`)
+		assert.Contains(t, svg, `>1 day</text>`)
+		assert.Contains(t, svg, `>Completions</text>`)
+	})
+	t.Run("Given side layout should be wider than stacked", func(t *testing.T) {
+		stacked := write(CardOptions{Options: testOptions(), Variant: CardLight, Layout: CardLayoutStacked})
+		side := write(CardOptions{Options: testOptions(), Variant: CardLight, Layout: CardLayoutSide})
+
+		assert.Contains(t, stacked, `<svg xmlns="http://www.w3.org/2000/svg" width="804" height="246"`)
+		assert.Contains(t, side, `<svg xmlns="http://www.w3.org/2000/svg" width="940" height="191"`)
+	})
+}
@@ -0,0 +1,167 @@
+package heatmap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/config"
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+)
+
+// Cell is a day of the heat map grid
+type Cell struct {
+	Row    int
+	Column int
+	// zero for the cells before or after the days of the grid, e.g. the days of
+	// the previous year in the first week of the year
+	Day time.Time
+	// the day is after today, its habits are not shown
+	Future bool
+	// nil when the day has no habits
+	HeatMap *models.HeatMap
+}
+
+// the cell is one of the days of the grid
+func (c Cell) IsDay() bool {
+	return !c.Day.IsZero()
+}
+
+// Grid is the heat map of the days between two days. A row is a weekday starting
+// from the week start and a column is a week.
+type Grid struct {
+	From      time.Time
+	To        time.Time
+	WeekStart time.Weekday
+	Cells     [][]Cell
+}
+
+// MonthLabel is the month whose first week starts at the column
+type MonthLabel struct {
+	Column int
+	Month  time.Month
+}
+
+// Creates the grid of the days between from and to, the heat maps of the days
+// after today are not shown
+func NewGrid(from time.Time, to time.Time, weekStart time.Weekday, heatmaps map[time.Time]*models.HeatMap, today time.Time) *Grid {
+	from, to = utils.ToDate(from), utils.ToDate(to)
+	start := utils.GetStartOfWeek(from, weekStart)
+	columns := int(to.Sub(start).Hours()/24)/7 + 1
+
+	cells := make([][]Cell, 7)
+	for row := range cells {
+		cells[row] = make([]Cell, columns)
+	}
+	day := start
+	for column := 0; column < columns; column++ {
+		for row := 0; row < 7; row++ {
+			cell := Cell{Row: row, Column: column}
+			if !day.Before(from) && !day.After(to) {
+				cell.Day = day
+				cell.Future = day.After(today)
+				if !cell.Future {
+					cell.HeatMap = heatmaps[day]
+				}
+			}
+			cells[row][column] = cell
+			day = day.AddDate(0, 0, 1)
+		}
+	}
+
+	return &Grid{From: from, To: to, WeekStart: weekStart, Cells: cells}
+}
+
+// Returns the first and the last day of the year
+func YearRange(year int) (time.Time, time.Time) {
+	return utils.CreateDate(year, 1, 1), utils.CreateDate(year, 12, 31)
+}
+
+// Returns the days of the 53 weeks that end with the week of today
+func LastYearRange(today time.Time, weekStart time.Weekday) (time.Time, time.Time) {
+	to := utils.GetStartOfWeek(today, weekStart).AddDate(0, 0, 6)
+	return to.AddDate(0, 0, -53*7+1), to
+}
+
+func (g *Grid) Columns() int {
+	return len(g.Cells[0])
+}
+
+// Returns the weekday of the row
+func (g *Grid) Weekday(row int) time.Weekday {
+	return time.Weekday((int(g.WeekStart) + row) % 7)
+}
+
+// Returns the days of the grid that are not in the future, in order
+func (g *Grid) Days() []Cell {
+	days := []Cell{}
+	for column := 0; column < g.Columns(); column++ {
+		for row := 0; row < 7; row++ {
+			if cell := g.Cells[row][column]; cell.IsDay() && !cell.Future {
+				days = append(days, cell)
+			}
+		}
+	}
+	return days
+}
+
+// Returns the columns the months start at, a month that starts too close to
+// the next one to fit its label is left out
+func (g *Grid) MonthLabels() []MonthLabel {
+	labels := []MonthLabel{}
+	for column := 0; column < g.Columns(); column++ {
+		for row := 0; row < 7; row++ {
+			cell := g.Cells[row][column]
+			if !cell.IsDay() || (cell.Day.Day() != 1 && !cell.Day.Equal(g.From)) {
+				continue
+			}
+			if n := len(labels); n > 0 && column-labels[n-1].Column < 3 {
+				labels = labels[:n-1]
+			}
+			labels = append(labels, MonthLabel{Column: column, Month: cell.Day.Month()})
+			break
+		}
+	}
+	return labels
+}
+
+// Returns the status value level of the day for the color scheme, zero when no
+// habits are completed
+func Level(heatmap *models.HeatMap, scheme config.HeatmapColorScheme) int {
+	return scheme.Level(heatmap.CompletedHabits, heatmap.TotalNumberOfHabits)
+}
+
+// Returns the value of the color scheme the cell is shown with
+func Shade(cell Cell, scheme config.HeatmapColorScheme) string {
+	heatmap := cell.HeatMap
+	switch {
+	case !cell.IsDay() || cell.Future:
+		return scheme.InvalidDayValue
+	case heatmap == nil:
+		return scheme.NoHabitsValue
+	case heatmap.Vacation || (heatmap.TotalNumberOfHabits == 0 && heatmap.SkippedHabits > 0):
+		return scheme.GetSkippedValue()
+	}
+
+	value, ok := scheme.StatusValues[Level(heatmap, scheme)]
+	if !ok {
+		return scheme.ZeroCompletedHabitValue
+	}
+	return value
+}
+
+// Describes the habits of the day, e.g. "3/5 habits on Jul 8th, 1 skipped"
+func Describe(cell Cell, locale utils.Locale) string {
+	day := locale.FormatMonthDay(cell.Day)
+	heatmap := cell.HeatMap
+	switch {
+	case heatmap == nil:
+		return fmt.Sprintf("No habits on %s", day)
+	case heatmap.Vacation:
+		return fmt.Sprintf("Vacation on %s", day)
+	case heatmap.SkippedHabits > 0:
+		return fmt.Sprintf("%d/%d habits on %s, %d skipped", heatmap.CompletedHabits, heatmap.TotalNumberOfHabits, day, heatmap.SkippedHabits)
+	default:
+		return fmt.Sprintf("%d/%d habits on %s", heatmap.CompletedHabits, heatmap.TotalNumberOfHabits, day)
+	}
+}
@@ -0,0 +1,102 @@
+package heatmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/config"
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGrid(t *testing.T) {
+	today := utils.CreateDate(2024, 7, 8)
+
+	t.Run("Given year should start the first week before the year", func(t *testing.T) {
+		from, to := YearRange(2024)
+		grid := NewGrid(from, to, time.Sunday, nil, today)
+
+		// 2024 starts on a monday and ends on a tuesday
+		assert.Equal(t, 53, grid.Columns())
+		assert.False(t, grid.Cells[0][0].IsDay())
+		assert.Equal(t, from, grid.Cells[1][0].Day)
+		assert.Equal(t, to, grid.Cells[2][52].Day)
+		assert.False(t, grid.Cells[3][52].IsDay())
+	})
+	t.Run("Given monday week start should shift the rows", func(t *testing.T) {
+		from, to := YearRange(2024)
+		grid := NewGrid(from, to, time.Monday, nil, today)
+
+		assert.Equal(t, from, grid.Cells[0][0].Day)
+		assert.Equal(t, time.Monday, grid.Weekday(0))
+		assert.Equal(t, time.Sunday, grid.Weekday(6))
+	})
+	t.Run("Given days after today should not show their habits", func(t *testing.T) {
+		from, to := YearRange(2024)
+		heatmaps := map[time.Time]*models.HeatMap{
+			today:                  {TotalNumberOfHabits: 1},
+			today.AddDate(0, 0, 1): {TotalNumberOfHabits: 1},
+		}
+		grid := NewGrid(from, to, time.Sunday, heatmaps, today)
+
+		days := grid.Days()
+		assert.Equal(t, today, days[len(days)-1].Day)
+		assert.NotNil(t, days[len(days)-1].HeatMap)
+		assert.True(t, grid.Cells[2][27].Future)
+		assert.Nil(t, grid.Cells[2][27].HeatMap)
+	})
+	t.Run("Given last year range should end with the week of today", func(t *testing.T) {
+		from, to := LastYearRange(today, time.Sunday)
+		grid := NewGrid(from, to, time.Sunday, nil, today)
+
+		assert.Equal(t, 53, grid.Columns())
+		assert.Equal(t, utils.CreateDate(2024, 7, 13), to)
+		assert.Equal(t, time.Sunday, from.Weekday())
+		assert.True(t, grid.Cells[0][0].IsDay())
+	})
+}
+
+func TestGrid_MonthLabels(t *testing.T) {
+	from, to := YearRange(2024)
+	grid := NewGrid(from, to, time.Sunday, nil, utils.CreateDate(2024, 12, 31))
+
+	labels := grid.MonthLabels()
+
+	assert.Len(t, labels, 12)
+	assert.Equal(t, MonthLabel{Column: 0, Month: time.January}, labels[0])
+	// february 1st is a thursday of the fifth week
+	assert.Equal(t, MonthLabel{Column: 4, Month: time.February}, labels[1])
+}
+
+func TestShade(t *testing.T) {
+	scheme := config.GetDefaultConfig().Gui.Theme.ColorSchemes["green"]
+	day := utils.CreateDate(2024, 7, 8)
+
+	tests := []struct {
+		name string
+		cell Cell
+		want string
+	}{
+		{"Given cell out of the grid should be invalid", Cell{}, scheme.InvalidDayValue},
+		{"Given future day should be invalid", Cell{Day: day, Future: true}, scheme.InvalidDayValue},
+		{"Given day without habits should have no habits", Cell{Day: day}, scheme.NoHabitsValue},
+		{"Given vacation should be skipped", Cell{Day: day, HeatMap: &models.HeatMap{Vacation: true}}, scheme.SkippedValue},
+		{"Given no completed habits should be zero", Cell{Day: day, HeatMap: &models.HeatMap{TotalNumberOfHabits: 2}}, scheme.ZeroCompletedHabitValue},
+		{"Given every habit completed should be the last level", Cell{Day: day, HeatMap: &models.HeatMap{TotalNumberOfHabits: 2, CompletedHabits: 2}}, scheme.StatusValues[5]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Shade(tt.cell, scheme))
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	locale, _ := utils.GetLocale("en")
+	day := utils.CreateDate(2024, 7, 8)
+
+	assert.Equal(t, "No habits on Jul 8th", Describe(Cell{Day: day}, locale))
+	assert.Equal(t, "Vacation on Jul 8th", Describe(Cell{Day: day, HeatMap: &models.HeatMap{Vacation: true}}, locale))
+	assert.Equal(t, "3/5 habits on Jul 8th, 1 skipped", Describe(Cell{Day: day, HeatMap: &models.HeatMap{TotalNumberOfHabits: 5, CompletedHabits: 3, SkippedHabits: 1}}, locale))
+}
@@ -0,0 +1,105 @@
+package heatmap
+
+import (
+	"github.com/metagunner/habheat/pkg/config"
+	"github.com/metagunner/habheat/pkg/utils"
+)
+
+// Options are the sizes and the colors of the drawn heat map
+type Options struct {
+	// width and height of a day in pixels
+	CellSize int
+	// space between the days in pixels
+	Gap    int
+	Scheme config.HeatmapColorScheme
+	Locale utils.Locale
+	// color of the days whose value in the scheme is not a color, e.g. spaces
+	EmptyColor string
+	TextColor  string
+	// transparent when it is empty
+	Background string
+	FontFamily string
+	// draw the shades from less to more below the grid
+	Legend bool
+}
+
+func DefaultOptions(scheme config.HeatmapColorScheme, locale utils.Locale) Options {
+	return Options{
+		CellSize:   11,
+		Gap:        3,
+		Scheme:     scheme,
+		Locale:     locale,
+		EmptyColor: "#ebedf0",
+		TextColor:  "#767676",
+		FontFamily: "sans-serif",
+		Legend:     true,
+	}
+}
+
+// width of a character of the labels, the labels are short so an estimate is enough
+const charWidth = 7
+
+// returns the fill color of the cell, empty when the cell is not drawn
+func (o Options) cellColor(cell Cell) string {
+	if color, ok := config.ParseColor(Shade(cell, o.Scheme)); ok {
+		return color.Hex()
+	}
+	if !cell.IsDay() || cell.Future {
+		return ""
+	}
+	return o.EmptyColor
+}
+
+// the space of the weekday labels on the left and the month labels on the top
+func (o Options) margins() (int, int) {
+	return o.CellSize * 3, o.CellSize * 2
+}
+
+func (o Options) step() int {
+	return o.CellSize + o.Gap
+}
+
+// Returns the width and the height of the drawn grid
+func (o Options) Size(grid *Grid) (int, int) {
+	left, top := o.margins()
+	width, height := left+grid.Columns()*o.step()-o.Gap, top+7*o.step()-o.Gap
+	if o.Legend {
+		height += o.CellSize + o.Gap*3
+	}
+	return width, height
+}
+
+type legendCell struct {
+	x     int
+	color string
+}
+
+// positions of the legend at the bottom right of the grid, the y is the top of the cells
+type legendLayout struct {
+	lessX int
+	moreX int
+	y     int
+	cells []legendCell
+}
+
+func (o Options) legend(grid *Grid) legendLayout {
+	width, height := o.Size(grid)
+	colors := []string{o.EmptyColor}
+	if color, ok := config.ParseColor(o.Scheme.ZeroCompletedHabitValue); ok {
+		colors[0] = color.Hex()
+	}
+	for level := 1; level <= o.Scheme.Levels(); level++ {
+		if color, ok := config.ParseColor(o.Scheme.StatusValues[level]); ok {
+			colors = append(colors, color.Hex())
+		}
+	}
+
+	layout := legendLayout{y: height - o.CellSize, moreX: width - len("More")*charWidth}
+	// the gap after the last cell separates it from the "More" label
+	start := layout.moreX - len(colors)*o.step()
+	layout.lessX = start - o.Gap - len("Less")*charWidth
+	for i, color := range colors {
+		layout.cells = append(layout.cells, legendCell{x: start + i*o.step(), color: color})
+	}
+	return layout
+}
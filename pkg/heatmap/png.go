@@ -0,0 +1,102 @@
+package heatmap
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/config"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// the Go font has the letters of every locale, e.g. "Mär" or "Şub"
+var parseFont = sync.OnceValues(func() (*opentype.Font, error) {
+	return opentype.Parse(goregular.TTF)
+})
+
+// Returns the face of the labels, the size of the font is the size of the
+// font of WriteSVG
+func newFace(opts Options) (font.Face, error) {
+	f, err := parseFont()
+	if err != nil {
+		return nil, err
+	}
+	return opentype.NewFace(f, &opentype.FaceOptions{Size: float64(opts.CellSize - 1), DPI: 72, Hinting: font.HintingFull})
+}
+
+// WritePNG draws the grid like WriteSVG
+func WritePNG(w io.Writer, grid *Grid, opts Options) error {
+	face, err := newFace(opts)
+	if err != nil {
+		return err
+	}
+	defer face.Close()
+
+	width, height := opts.Size(grid)
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	if opts.Background != "" {
+		draw.Draw(img, img.Bounds(), image.NewUniform(parseRGBA(opts.Background)), image.Point{}, draw.Src)
+	}
+	drawGrid(img, grid, opts, face, 0, 0)
+	return png.Encode(w, img)
+}
+
+// draws the labels, the days and the legend of the grid at the offset
+func drawGrid(img draw.Image, grid *Grid, opts Options, face font.Face, x int, y int) {
+	left, top := opts.margins()
+	step := opts.step()
+	drawer := &font.Drawer{Dst: img, Src: image.NewUniform(parseRGBA(opts.TextColor)), Face: face}
+	drawText := func(text string, x int, y int) {
+		drawer.Dot = fixed.P(x, y)
+		drawer.DrawString(text)
+	}
+
+	for _, label := range grid.MonthLabels() {
+		drawText(opts.Locale.MonthName(label.Month), x+left+label.Column*step, y+top-opts.Gap*2)
+	}
+	for row := 0; row < 7; row++ {
+		weekday := grid.Weekday(row)
+		if weekday != time.Monday && weekday != time.Wednesday && weekday != time.Friday {
+			continue
+		}
+		drawText(opts.Locale.WeekdayName(weekday), x, y+top+row*step+opts.CellSize-2)
+	}
+
+	for column := 0; column < grid.Columns(); column++ {
+		for row := 0; row < 7; row++ {
+			if color := opts.cellColor(grid.Cells[row][column]); color != "" {
+				fillCell(img, x+left+column*step, y+top+row*step, opts.CellSize, color)
+			}
+		}
+	}
+
+	if opts.Legend {
+		legend := opts.legend(grid)
+		drawText("Less", x+legend.lessX, y+legend.y+opts.CellSize-2)
+		drawText("More", x+legend.moreX, y+legend.y+opts.CellSize-2)
+		for _, cell := range legend.cells {
+			fillCell(img, x+cell.x, y+legend.y, opts.CellSize, cell.color)
+		}
+	}
+}
+
+func fillCell(img draw.Image, x int, y int, size int, hex string) {
+	rect := image.Rect(x, y, x+size, y+size)
+	draw.Draw(img, rect, image.NewUniform(parseRGBA(hex)), image.Point{}, draw.Src)
+}
+
+// parses a color of the options, the values that are not colors are transparent
+func parseRGBA(value string) color.RGBA {
+	c, ok := config.ParseColor(value)
+	if !ok {
+		return color.RGBA{}
+	}
+	return color.RGBA{R: c.R, G: c.G, B: c.B, A: 255}
+}
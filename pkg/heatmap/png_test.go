@@ -0,0 +1,75 @@
+package heatmap
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritePNG(t *testing.T) {
+	from, to := YearRange(2024)
+	// the first day is on the second row of the first column
+	heatmaps := map[time.Time]*models.HeatMap{from: {TotalNumberOfHabits: 1, CompletedHabits: 1}}
+	grid := NewGrid(from, to, time.Sunday, heatmaps, to)
+	opts := testOptions()
+	opts.Background = "#ffffff"
+
+	var b bytes.Buffer
+	assert.NoError(t, WritePNG(&b, grid, opts))
+
+	img, err := png.Decode(&b)
+	assert.NoError(t, err)
+	width, height := opts.Size(grid)
+	assert.Equal(t, width, img.Bounds().Dx())
+	assert.Equal(t, height, img.Bounds().Dy())
+
+	left, top := opts.margins()
+	assert.Equal(t, color.RGBA{R: 0x87, G: 0xff, B: 0x00, A: 255}, color.RGBAModel.Convert(img.At(left+1, top+opts.step()+1)))
+	assert.Equal(t, color.RGBA{R: 0xeb, G: 0xed, B: 0xf0, A: 255}, color.RGBAModel.Convert(img.At(left+opts.step()+1, top+1)))
+	// the cell before the year is not drawn
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, color.RGBAModel.Convert(img.At(left+1, top+1)))
+}
+
+func TestWritePNG_Locale(t *testing.T) {
+	opts := testOptions()
+	opts.Background = "#ffffff"
+
+	t.Run("Given every locale should have the glyphs of the labels", func(t *testing.T) {
+		face, err := newFace(opts)
+		assert.NoError(t, err)
+		defer face.Close()
+
+		for _, language := range utils.GetLanguages() {
+			locale, _ := utils.GetLocale(language)
+			for _, name := range append(locale.Months[:], locale.Weekdays[:]...) {
+				for _, r := range name {
+					_, ok := face.GlyphAdvance(r)
+					assert.True(t, ok, "%s has no glyph of %q in %q", language, r, name)
+				}
+			}
+		}
+	})
+	t.Run("Given German locale should draw the umlaut", func(t *testing.T) {
+		from, to := YearRange(2024)
+		grid := NewGrid(from, to, time.Sunday, map[time.Time]*models.HeatMap{}, to)
+		write := func(locale utils.Locale) []byte {
+			opts.Locale = locale
+			var b bytes.Buffer
+			assert.NoError(t, WritePNG(&b, grid, opts))
+			return b.Bytes()
+		}
+
+		german, _ := utils.GetLocale("de")
+		// a font without the umlaut draws its missing glyph like the private use rune
+		missing := german
+		missing.Months[time.March-1] = "M\ue000r"
+
+		assert.NotEqual(t, write(missing), write(german))
+	})
+}
@@ -0,0 +1,71 @@
+package heatmap
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"time"
+)
+
+// WriteSVG draws the grid with the month and the weekday labels like the heat
+// map of the terminal. Every day has its description as a tooltip and its date
+// in the data-day attribute.
+func WriteSVG(w io.Writer, grid *Grid, opts Options) error {
+	width, height := opts.Size(grid)
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" class="heatmap">`+"\n", width, height, width, height)
+	if opts.Background != "" {
+		fmt.Fprintf(b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", html.EscapeString(opts.Background))
+	}
+	writeSVGGrid(b, grid, opts, 0, 0)
+	fmt.Fprintln(b, `</svg>`)
+	return b.Flush()
+}
+
+// writes the labels, the days and the legend of the grid at the offset
+func writeSVGGrid(b *bufio.Writer, grid *Grid, opts Options, x int, y int) {
+	left, top := opts.margins()
+	step := opts.step()
+	fontSize := opts.CellSize - 1
+
+	fmt.Fprintf(b, `<g font-family="%s" font-size="%d" fill="%s">`+"\n", html.EscapeString(opts.FontFamily), fontSize, html.EscapeString(opts.TextColor))
+	for _, label := range grid.MonthLabels() {
+		fmt.Fprintf(b, `<text x="%d" y="%d">%s</text>`+"\n", x+left+label.Column*step, y+top-opts.Gap*2, html.EscapeString(opts.Locale.MonthName(label.Month)))
+	}
+	for row := 0; row < 7; row++ {
+		weekday := grid.Weekday(row)
+		if weekday != time.Monday && weekday != time.Wednesday && weekday != time.Friday {
+			continue
+		}
+		fmt.Fprintf(b, `<text x="%d" y="%d">%s</text>`+"\n", x, y+top+row*step+fontSize-1, html.EscapeString(opts.Locale.WeekdayName(weekday)))
+	}
+	if opts.Legend {
+		legend := opts.legend(grid)
+		fmt.Fprintf(b, `<text x="%d" y="%d">Less</text>`+"\n", x+legend.lessX, y+legend.y+fontSize-1)
+		fmt.Fprintf(b, `<text x="%d" y="%d">More</text>`+"\n", x+legend.moreX, y+legend.y+fontSize-1)
+	}
+	fmt.Fprintln(b, `</g>`)
+
+	for column := 0; column < grid.Columns(); column++ {
+		for row := 0; row < 7; row++ {
+			cell := grid.Cells[row][column]
+			color := opts.cellColor(cell)
+			if color == "" {
+				continue
+			}
+			fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"`, x+left+column*step, y+top+row*step, opts.CellSize, opts.CellSize, color)
+			if cell.IsDay() && !cell.Future {
+				fmt.Fprintf(b, ` data-day="%s"><title>%s</title></rect>`+"\n", cell.Day.Format(time.DateOnly), html.EscapeString(Describe(cell, opts.Locale)))
+			} else {
+				fmt.Fprintln(b, `/>`)
+			}
+		}
+	}
+	if opts.Legend {
+		legend := opts.legend(grid)
+		for _, cell := range legend.cells {
+			fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"/>`+"\n", x+cell.x, y+legend.y, opts.CellSize, opts.CellSize, cell.color)
+		}
+	}
+}
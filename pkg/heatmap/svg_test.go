@@ -0,0 +1,47 @@
+package heatmap
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/config"
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func testOptions() Options {
+	locale, _ := utils.GetLocale("en")
+	return DefaultOptions(config.GetDefaultConfig().Gui.Theme.ColorSchemes["green"], locale)
+}
+
+func TestWriteSVG(t *testing.T) {
+	from, to := YearRange(2024)
+	day := utils.CreateDate(2024, 3, 5)
+	heatmaps := map[time.Time]*models.HeatMap{day: {TotalNumberOfHabits: 2, CompletedHabits: 2}}
+	grid := NewGrid(from, to, time.Sunday, heatmaps, utils.CreateDate(2024, 6, 30))
+
+	t.Run("Given grid should draw the days until today", func(t *testing.T) {
+		var b strings.Builder
+
+		assert.NoError(t, WriteSVG(&b, grid, testOptions()))
+
+		svg := b.String()
+		assert.Contains(t, svg, `fill="#87ff00" data-day="2024-03-05"><title>2/2 habits on Mar 5th</title>`)
+		assert.Contains(t, svg, `data-day="2024-06-30"`)
+		assert.NotContains(t, svg, `data-day="2024-07-01"`)
+		assert.Contains(t, svg, `>Mar</text>`)
+		assert.Contains(t, svg, `>Wed</text>`)
+		assert.Contains(t, svg, `>Less</text>`)
+	})
+	t.Run("Given no legend should not draw it", func(t *testing.T) {
+		var b strings.Builder
+		opts := testOptions()
+		opts.Legend = false
+
+		assert.NoError(t, WriteSVG(&b, grid, opts))
+
+		assert.NotContains(t, b.String(), `>Less</text>`)
+	})
+}
@@ -0,0 +1,120 @@
+package journal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Item is a checkbox of the habits block, e.g. "- [x] Meditate"
+type Item struct {
+	Title     string
+	Completed bool
+	// written as "- [-] Meditate"
+	Skipped bool
+}
+
+var itemRegexp = regexp.MustCompile(`^\s*[-*+] \[([ xX-])\] (.*\S)\s*$`)
+
+func parseItem(line string) (Item, bool) {
+	match := itemRegexp.FindStringSubmatch(line)
+	if match == nil {
+		return Item{}, false
+	}
+	return Item{Title: match[2], Completed: match[1] == "x" || match[1] == "X", Skipped: match[1] == "-"}, true
+}
+
+func (i Item) String() string {
+	return "- [" + i.mark() + "] " + i.Title
+}
+
+// the mark between the brackets of the checkbox
+func (i Item) mark() string {
+	switch {
+	case i.Completed:
+		return "x"
+	case i.Skipped:
+		return "-"
+	default:
+		return " "
+	}
+}
+
+// Block is the checklist below the heading of a note
+type Block struct {
+	lines []string
+	// index of the heading line, -1 when the note doesn't have the heading
+	heading int
+	// lines of the checklist, the end is not included. Both are the line after
+	// the heading when the checklist is empty.
+	start int
+	end   int
+	Items []Item
+}
+
+// Parses the checklist that follows the heading, the blank lines between the
+// heading and the checklist are allowed. The first item of a title is used
+// when a title is listed more than once.
+func ParseBlock(content string, heading string) *Block {
+	b := &Block{lines: strings.Split(content, "\n"), heading: -1}
+	for i, line := range b.lines {
+		if strings.TrimSpace(line) == heading {
+			b.heading = i
+			break
+		}
+	}
+	if b.heading == -1 {
+		return b
+	}
+
+	b.start, b.end = b.heading+1, b.heading+1
+	titles := map[string]bool{}
+	for i := b.heading + 1; i < len(b.lines); i++ {
+		line := b.lines[i]
+		if strings.TrimSpace(line) == "" && len(b.Items) == 0 {
+			continue
+		}
+		item, ok := parseItem(line)
+		if !ok {
+			break
+		}
+		if len(b.Items) == 0 {
+			b.start = i
+		}
+		b.end = i + 1
+		if !titles[item.Title] {
+			titles[item.Title] = true
+			b.Items = append(b.Items, item)
+		}
+	}
+	return b
+}
+
+// Returns the content of the note with the items of the block, the heading and
+// the items are added to the end of the note when it doesn't have the heading
+func (b *Block) Render(heading string) string {
+	items := make([]string, 0, len(b.Items))
+	for _, item := range b.Items {
+		items = append(items, item.String())
+	}
+
+	if b.heading == -1 {
+		content := strings.TrimRight(strings.Join(b.lines, "\n"), "\n")
+		if content != "" {
+			content += "\n\n"
+		}
+		return content + heading + "\n" + strings.Join(items, "\n") + "\n"
+	}
+
+	lines := append(append(append([]string{}, b.lines[:b.start]...), items...), b.lines[b.end:]...)
+	return strings.Join(lines, "\n")
+}
+
+// Returns the item with the title
+func (b *Block) find(title string) *Item {
+	for i := range b.Items {
+		if b.Items[i].Title == title {
+			return &b.Items[i]
+		}
+	}
+	return nil
+}
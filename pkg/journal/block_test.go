@@ -0,0 +1,65 @@
+package journal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBlock(t *testing.T) {
+	t.Run("Given note with heading should parse the checklist below it", func(t *testing.T) {
+		content := "# 2024-07-08\n\n## Habits\n\n- [x] Read\n* [ ] Run\n- [-] Meditate\n  - [X] Read\nSome text\n- [ ] Not a habit\n"
+
+		block := ParseBlock(content, "## Habits")
+
+		assert.Equal(t, []Item{
+			{Title: "Read", Completed: true},
+			{Title: "Run"},
+			{Title: "Meditate", Skipped: true},
+		}, block.Items)
+	})
+
+	t.Run("Given note without heading should have no items", func(t *testing.T) {
+		block := ParseBlock("- [x] Read\n", "## Habits")
+
+		assert.Empty(t, block.Items)
+	})
+}
+
+func TestBlock_Render(t *testing.T) {
+	t.Run("Given note with heading should replace the checklist and keep the rest", func(t *testing.T) {
+		content := "# 2024-07-08\n## Habits\n- [ ] Read\n* [x] Run\n\nSome text\n"
+		block := ParseBlock(content, "## Habits")
+		block.Items[0].Completed = true
+		block.Items = append(block.Items, Item{Title: "Meditate", Skipped: true})
+
+		rendered := block.Render("## Habits")
+
+		assert.Equal(t, "# 2024-07-08\n## Habits\n- [x] Read\n- [x] Run\n- [-] Meditate\n\nSome text\n", rendered)
+	})
+
+	t.Run("Given note with empty checklist should add the items below the heading", func(t *testing.T) {
+		block := ParseBlock("## Habits\n\nSome text", "## Habits")
+		block.Items = []Item{{Title: "Read"}}
+
+		rendered := block.Render("## Habits")
+
+		assert.Equal(t, "## Habits\n- [ ] Read\n\nSome text", rendered)
+	})
+
+	t.Run("Given note without heading should add the heading to the end", func(t *testing.T) {
+		block := ParseBlock("# 2024-07-08\nSome text\n\n", "## Habits")
+		block.Items = []Item{{Title: "Read", Completed: true}}
+
+		rendered := block.Render("## Habits")
+
+		assert.Equal(t, "# 2024-07-08\nSome text\n\n## Habits\n- [x] Read\n", rendered)
+	})
+
+	t.Run("Given empty note should write the heading and the items", func(t *testing.T) {
+		block := ParseBlock("", "## Habits")
+		block.Items = []Item{{Title: "Read"}}
+
+		assert.Equal(t, "## Habits\n- [ ] Read\n", block.Render("## Habits"))
+	})
+}
@@ -0,0 +1,292 @@
+package journal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/app"
+	"github.com/metagunner/habheat/pkg/models"
+)
+
+// name of the file in the journal directory that keeps the checklists of the last syncs
+const stateFileName = ".habheat-sync.json"
+
+// Syncer keeps the habits block of the daily notes, e.g. 2024-07-08.md, and the
+// habits of the day the same. A checkbox that differs from the last sync of the
+// day is a change of the note, a habit updated after the last sync is a change
+// of habheat. A change on one side is copied to the other side, a habit changed
+// on both sides is a conflict and is left as it is. A synced habit removed from
+// one side is removed from the other side, the habits go to the trash.
+type Syncer struct {
+	HabitService models.HabitService
+	// directory of the daily notes
+	Dir     string
+	Heading string
+	// creates the notes of the days with habits that don't have a note
+	CreateNotes bool
+	Now         func() time.Time
+}
+
+// Conflict is a habit changed both in the note and in habheat since the last sync
+type Conflict struct {
+	Day   time.Time
+	Title string
+	// the checkbox in the note, nil when it was removed from the note
+	Note *Item
+	// the habit in habheat, nil when it was deleted
+	Habit *Item
+}
+
+// SkippedItem is a checkbox of a note that can't be a habit, e.g. its title is
+// too long or its habit is archived
+type SkippedItem struct {
+	Day   time.Time
+	Title string
+	Err   error
+}
+
+type Result struct {
+	// habits created from the checkboxes of the notes
+	CreatedHabits int
+	// habits updated from the checkboxes of the notes
+	UpdatedHabits int
+	// habits moved to the trash as their checkboxes were removed from the notes
+	DeletedHabits int
+	// notes written with the changes of the habits
+	WrittenNotes int
+	Conflicts    []Conflict
+	// checkboxes that are not created as habits, they are kept in the notes
+	Skipped []SkippedItem
+}
+
+func NewSyncer(habitService models.HabitService, dir string, heading string) *Syncer {
+	return &Syncer{HabitService: habitService, Dir: dir, Heading: heading, Now: time.Now}
+}
+
+// the state of a day after its last sync
+type dayState struct {
+	SyncedAt time.Time `json:"synced_at"`
+	// the marks of the checkboxes by their titles, e.g. "x" for a done habit
+	Items map[string]string `json:"items"`
+}
+
+// Syncs the notes of the days
+func (s *Syncer) Sync(ctx context.Context, days []time.Time) (*Result, error) {
+	state, err := s.loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for _, day := range days {
+		key := day.Format(time.DateOnly)
+		synced, err := s.syncDay(ctx, day, state[key], result)
+		if err != nil {
+			// the days synced before the error are not synced again
+			return nil, errors.Join(err, s.saveState(state))
+		}
+		if synced != nil {
+			state[key] = synced
+		}
+	}
+
+	return result, s.saveState(state)
+}
+
+// Syncs the note of the day, returns the new state of the day or nil when the
+// day has a conflict, a skipped checkbox or there is nothing to sync
+func (s *Syncer) syncDay(ctx context.Context, day time.Time, last *dayState, result *Result) (*dayState, error) {
+	path := filepath.Join(s.Dir, day.Format(time.DateOnly)+".md")
+	content, modTime, err := readNote(path)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := s.HabitService.GetAllByDay(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+	if content == "" && modTime.IsZero() && (!s.CreateNotes || len(chain.Habits) == 0) {
+		return nil, nil
+	}
+
+	block := ParseBlock(content, s.Heading)
+	conflicts, skipped := len(result.Conflicts), len(result.Skipped)
+	for _, habit := range chain.Habits {
+		habitItem := Item{Title: habit.Title.String(), Completed: habit.IsCompleted, Skipped: habit.IsSkipped}
+		item := block.find(habitItem.Title)
+		if item == nil {
+			if _, ok := last.item(habitItem.Title); !ok {
+				block.Items = append(block.Items, habitItem)
+				continue
+			}
+			// the checkbox was removed from the note
+			if habit.UpdatedAt.After(last.SyncedAt) {
+				result.Conflicts = append(result.Conflicts, Conflict{Day: day, Title: habitItem.Title, Habit: &habitItem})
+				continue
+			}
+			if err := s.HabitService.Delete(ctx, habit.Id); err != nil {
+				return nil, err
+			}
+			result.DeletedHabits++
+			continue
+		}
+		if *item == habitItem {
+			continue
+		}
+
+		var noteWins bool
+		if last == nil {
+			// never synced, the last change wins
+			noteWins = modTime.After(habit.UpdatedAt)
+		} else {
+			mark, ok := last.item(item.Title)
+			noteChanged := !ok || mark != item.mark()
+			habitChanged := habit.UpdatedAt.After(last.SyncedAt)
+			if noteChanged && habitChanged {
+				noteItem := *item
+				result.Conflicts = append(result.Conflicts, Conflict{Day: day, Title: habitItem.Title, Note: &noteItem, Habit: &habitItem})
+				continue
+			}
+			noteWins = noteChanged
+		}
+
+		if !noteWins {
+			*item = habitItem
+			continue
+		}
+		if item.Completed != habit.IsCompleted {
+			habit.ToggleCompletion()
+		}
+		if item.Skipped != habit.IsSkipped {
+			habit.ToggleSkip()
+		}
+		// the change is not a change of habheat in the next sync
+		habit.UpdatedAt = s.Now().UTC()
+		if err := s.HabitService.Update(ctx, habit); err != nil {
+			return nil, err
+		}
+		result.UpdatedHabits++
+	}
+
+	// the checkboxes without a habit are new habits of the day, unless the habit
+	// was synced and deleted since
+	items := make([]Item, 0, len(block.Items))
+	for _, item := range block.Items {
+		if _, found := lookupHabit(chain, item.Title); found {
+			items = append(items, item)
+			continue
+		}
+		if mark, ok := last.item(item.Title); ok {
+			if mark != item.mark() {
+				noteItem := item
+				result.Conflicts = append(result.Conflicts, Conflict{Day: day, Title: item.Title, Note: &noteItem})
+				items = append(items, item)
+			}
+			continue
+		}
+		items = append(items, item)
+		// an invalid checkbox doesn't stop the sync of the other checkboxes
+		if err := s.createHabit(ctx, day, item); app.ErrorCode(err) == app.EINVALID || app.ErrorCode(err) == app.ECONFLICT {
+			result.Skipped = append(result.Skipped, SkippedItem{Day: day, Title: item.Title, Err: err})
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		result.CreatedHabits++
+	}
+	block.Items = items
+
+	if rendered := block.Render(s.Heading); rendered != content {
+		if err := os.WriteFile(path, []byte(rendered), 0o644); err != nil {
+			return nil, err
+		}
+		result.WrittenNotes++
+	}
+	// the day is synced again until the conflicts and the skipped checkboxes are fixed
+	if len(result.Conflicts) > conflicts || len(result.Skipped) > skipped {
+		return nil, nil
+	}
+
+	synced := &dayState{SyncedAt: s.Now().UTC(), Items: make(map[string]string, len(block.Items))}
+	for _, item := range block.Items {
+		synced.Items[item.Title] = item.mark()
+	}
+	return synced, nil
+}
+
+// Creates the habit of the checkbox
+func (s *Syncer) createHabit(ctx context.Context, day time.Time, item Item) error {
+	title, err := models.CreateHabitTitle(item.Title)
+	if err != nil {
+		return err
+	}
+	habit, err := models.CreateHabit(title, day, item.Completed)
+	if err != nil {
+		return err
+	}
+	habit.IsSkipped = item.Skipped && !item.Completed
+	habit.CreatedAt, habit.UpdatedAt = s.Now().UTC(), s.Now().UTC()
+	return s.HabitService.Create(ctx, habit)
+}
+
+// Returns the mark of the checkbox at the last sync, the day may not be synced yet
+func (d *dayState) item(title string) (string, bool) {
+	if d == nil {
+		return "", false
+	}
+	mark, ok := d.Items[title]
+	return mark, ok
+}
+
+func lookupHabit(chain *models.Chain, title string) (*models.Habit, bool) {
+	for _, habit := range chain.Habits {
+		if habit.Title.String() == title {
+			return habit, true
+		}
+	}
+	return nil, false
+}
+
+// returns the content and the modification time of the note, both are empty
+// when the note doesn't exist
+func readNote(path string) (string, time.Time, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", time.Time{}, nil
+	} else if err != nil {
+		return "", time.Time{}, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return string(content), info.ModTime(), nil
+}
+
+// the states of the days by the YYYY-MM-DD days
+func (s *Syncer) loadState() (map[string]*dayState, error) {
+	state := map[string]*dayState{}
+	content, err := os.ReadFile(filepath.Join(s.Dir, stateFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (s *Syncer) saveState(state map[string]*dayState) error {
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.Dir, stateFileName), content, 0o644)
+}
@@ -0,0 +1,224 @@
+package journal
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/database"
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+var testDB *database.DB
+
+func TestMain(m *testing.M) {
+	db, err := database.SetupTestDB()
+	if err != nil {
+		log.Fatal(err)
+	}
+	testDB = db
+	code := m.Run()
+	if err := testDB.Close(); err != nil {
+		log.Fatal(err)
+	}
+	os.Exit(code)
+}
+
+func TestSyncer_Sync(t *testing.T) {
+	ctx := context.Background()
+	service := database.NewHabitService(testDB)
+	dir := t.TempDir()
+	day := utils.CreateDate(2005, 7, 8)
+	path := filepath.Join(dir, "2005-07-08.md")
+	start := time.Date(2005, 7, 8, 20, 0, 0, 0, time.UTC)
+
+	syncer := NewSyncer(service, dir, "## Habits")
+	sync := func(now time.Time) *Result {
+		syncer.Now = func() time.Time { return now }
+		result, err := syncer.Sync(ctx, []time.Time{day})
+		assert.NoError(t, err)
+		return result
+	}
+	writeNote := func(content string, modTime time.Time) {
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		assert.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	readNote := func() string {
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		return string(content)
+	}
+	getHabit := func(title string) *models.Habit {
+		chain, err := service.GetAllByDay(ctx, day)
+		assert.NoError(t, err)
+		habit, _ := lookupHabit(chain, title)
+		return habit
+	}
+	updateHabit := func(title string, change func(h *models.Habit), updatedAt time.Time) {
+		habit := getHabit(title)
+		change(habit)
+		habit.UpdatedAt = updatedAt
+		assert.NoError(t, service.Update(ctx, habit))
+	}
+
+	for _, title := range []string{"Read", "Run"} {
+		habitTitle, _ := models.CreateHabitTitle(title)
+		habit, _ := models.CreateHabit(habitTitle, day, false)
+		habit.UpdatedAt = start.Add(-time.Hour)
+		assert.NoError(t, service.Create(ctx, habit))
+	}
+
+	t.Run("Given missing note should write it only when the notes are created", func(t *testing.T) {
+		result := sync(start)
+		assert.Equal(t, &Result{}, result)
+		assert.NoFileExists(t, path)
+
+		syncer.CreateNotes = true
+		result = sync(start)
+		syncer.CreateNotes = false
+
+		assert.Equal(t, 1, result.WrittenNotes)
+		assert.Equal(t, "## Habits\n- [ ] Read\n- [ ] Run\n", readNote())
+	})
+
+	t.Run("Given first sync should take the last change and add the missing habits", func(t *testing.T) {
+		assert.NoError(t, os.Remove(filepath.Join(dir, stateFileName)))
+		writeNote("# Monday\n## Habits\n- [x] Read\n- [-] Meditate\n\nNotes\n", start)
+		updateHabit("Run", func(h *models.Habit) { h.ToggleCompletion() }, start.Add(time.Minute))
+
+		result := sync(start.Add(time.Hour))
+
+		assert.Equal(t, &Result{CreatedHabits: 1, UpdatedHabits: 1, WrittenNotes: 1}, result)
+		assert.Equal(t, "# Monday\n## Habits\n- [x] Read\n- [-] Meditate\n- [x] Run\n\nNotes\n", readNote())
+		assert.True(t, getHabit("Read").IsCompleted)
+		assert.True(t, getHabit("Meditate").IsSkipped)
+	})
+
+	t.Run("Given changes on one side should copy them to the other side", func(t *testing.T) {
+		writeNote("# Monday\n## Habits\n- [ ] Read\n- [-] Meditate\n- [x] Run\n\nNotes\n", start.Add(2*time.Hour))
+		updateHabit("Meditate", func(h *models.Habit) { h.ToggleCompletion() }, start.Add(2*time.Hour))
+
+		result := sync(start.Add(3 * time.Hour))
+
+		assert.Equal(t, &Result{UpdatedHabits: 1, WrittenNotes: 1}, result)
+		assert.Equal(t, "# Monday\n## Habits\n- [ ] Read\n- [x] Meditate\n- [x] Run\n\nNotes\n", readNote())
+		assert.False(t, getHabit("Read").IsCompleted)
+	})
+
+	t.Run("Given habit changed on both sides should report a conflict and keep both", func(t *testing.T) {
+		writeNote("# Monday\n## Habits\n- [ ] Read\n- [x] Meditate\n- [-] Run\n\nNotes\n", start.Add(4*time.Hour))
+		updateHabit("Run", func(h *models.Habit) { h.ToggleCompletion() }, start.Add(4*time.Hour))
+
+		result := sync(start.Add(5 * time.Hour))
+
+		assert.Equal(t, []Conflict{{
+			Day:   day,
+			Title: "Run",
+			Note:  &Item{Title: "Run", Skipped: true},
+			Habit: &Item{Title: "Run"},
+		}}, result.Conflicts)
+		assert.Equal(t, "# Monday\n## Habits\n- [ ] Read\n- [x] Meditate\n- [-] Run\n\nNotes\n", readNote())
+		assert.False(t, getHabit("Run").IsSkipped)
+
+		// the day is not marked as synced, the conflict is reported until it is resolved
+		result = sync(start.Add(6 * time.Hour))
+		assert.Len(t, result.Conflicts, 1)
+
+		updateHabit("Run", func(h *models.Habit) { h.ToggleSkip() }, start.Add(7*time.Hour))
+		result = sync(start.Add(8 * time.Hour))
+		assert.Empty(t, result.Conflicts)
+	})
+
+	t.Run("Given checkbox removed from the note should trash the habit", func(t *testing.T) {
+		writeNote("# Monday\n## Habits\n- [x] Meditate\n- [-] Run\n\nNotes\n", start.Add(9*time.Hour))
+
+		result := sync(start.Add(10 * time.Hour))
+
+		assert.Equal(t, &Result{DeletedHabits: 1}, result)
+		assert.Nil(t, getHabit("Read"))
+		trash, err := service.Trash(ctx)
+		assert.NoError(t, err)
+		assert.True(t, lo.ContainsBy(trash, func(h *models.Habit) bool { return h.Title.String() == "Read" && h.Day.Equal(day) }))
+	})
+
+	t.Run("Given habit deleted in habheat should remove its checkbox", func(t *testing.T) {
+		assert.NoError(t, service.Delete(ctx, getHabit("Meditate").Id))
+
+		result := sync(start.Add(11 * time.Hour))
+
+		assert.Equal(t, &Result{WrittenNotes: 1}, result)
+		assert.Equal(t, "# Monday\n## Habits\n- [-] Run\n\nNotes\n", readNote())
+		assert.Nil(t, getHabit("Meditate"))
+	})
+
+	t.Run("Given removed habit changed on the other side should report a conflict", func(t *testing.T) {
+		writeNote("# Monday\n## Habits\n\nNotes\n", start.Add(12*time.Hour))
+		updateHabit("Run", func(h *models.Habit) { h.ToggleCompletion() }, start.Add(12*time.Hour))
+
+		result := sync(start.Add(13 * time.Hour))
+
+		assert.Equal(t, []Conflict{{Day: day, Title: "Run", Habit: &Item{Title: "Run", Completed: true}}}, result.Conflicts)
+		assert.NotNil(t, getHabit("Run"))
+
+		// the checkbox of the deleted habit is kept when it is changed in the note
+		assert.NoError(t, service.Delete(ctx, getHabit("Run").Id))
+		writeNote("# Monday\n## Habits\n- [x] Run\n\nNotes\n", start.Add(14*time.Hour))
+
+		result = sync(start.Add(15 * time.Hour))
+
+		assert.Equal(t, []Conflict{{Day: day, Title: "Run", Note: &Item{Title: "Run", Completed: true}}}, result.Conflicts)
+		assert.Equal(t, "# Monday\n## Habits\n- [x] Run\n\nNotes\n", readNote())
+		assert.Nil(t, getHabit("Run"))
+	})
+
+	t.Run("Given failing day should keep the state of the days synced before it", func(t *testing.T) {
+		// the note of the next day can't be read
+		assert.NoError(t, os.Mkdir(filepath.Join(dir, "2005-07-09.md"), 0o755))
+		writeNote("# Monday\n## Habits\n\nNotes\n", start.Add(16*time.Hour))
+		syncer.Now = func() time.Time { return start.Add(17 * time.Hour) }
+
+		_, err := syncer.Sync(ctx, []time.Time{day, day.AddDate(0, 0, 1)})
+
+		assert.Error(t, err)
+		state, err := syncer.loadState()
+		assert.NoError(t, err)
+		assert.Equal(t, &dayState{SyncedAt: start.Add(17 * time.Hour), Items: map[string]string{}}, state["2005-07-08"])
+	})
+}
+
+func TestSyncer_SkippedItem(t *testing.T) {
+	ctx := context.Background()
+	service := database.NewHabitService(testDB)
+	dir := t.TempDir()
+	day := utils.CreateDate(2005, 8, 1)
+	longTitle := strings.Repeat("a", 251)
+	note := "## Habits\n- [ ] " + longTitle + "\n- [x] Walk\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "2005-08-01.md"), []byte(note), 0o644))
+	syncer := NewSyncer(service, dir, "## Habits")
+
+	t.Run("Given invalid checkbox should skip it and sync the others", func(t *testing.T) {
+		result, err := syncer.Sync(ctx, []time.Time{day})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.CreatedHabits)
+		assert.Len(t, result.Skipped, 1)
+		assert.Equal(t, longTitle, result.Skipped[0].Title)
+		assert.ErrorIs(t, result.Skipped[0].Err, models.ErrInvalidHabitTitle)
+		chain, err := service.GetAllByDay(ctx, day)
+		assert.NoError(t, err)
+		assert.Len(t, chain.Habits, 1)
+		assert.True(t, chain.Habits[0].IsCompleted)
+
+		// the day is synced again
+		state, err := syncer.loadState()
+		assert.NoError(t, err)
+		assert.Nil(t, state["2005-08-01"])
+	})
+}
@@ -3,18 +3,32 @@ package models
 import (
 	"context"
 	"time"
+	"unicode/utf8"
 
 	"github.com/metagunner/habheat/pkg/app"
+	"github.com/metagunner/habheat/pkg/utils"
 )
 
 var ErrInvalidHabitTitle = app.Errorf(app.EINVALID, "Invalid habit title.")
+var ErrInvalidHabitNote = app.Errorf(app.EINVALID, "The note is longer than 1000 characters.")
 
 type Habit struct {
 	Id          HabitId    `json:"id"`
 	Title       HabitTitle `json:"title"`
 	Day         time.Time  `json:"day"`
 	IsCompleted bool       `json:"is_completed"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	// Skipped habits are excused for the day, they are neither completed nor failed
+	IsSkipped bool `json:"is_skipped"`
+	// Position of the habit in its day
+	Position int `json:"position"`
+	// Free text note for the day
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Archived habits are not carried to new days, their completions still count
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	// Deleted habits are kept in the trash until it is emptied
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type (
@@ -35,13 +49,34 @@ func (ht HabitTitle) String() string {
 }
 
 func CreateHabit(title HabitTitle, day time.Time, isCompleted bool) (*Habit, error) {
-	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
-	habit := &Habit{Title: title, Day: day, UpdatedAt: time.Now().UTC(), IsCompleted: isCompleted}
+	day = utils.ToDate(day)
+	now := time.Now().UTC()
+	habit := &Habit{Title: title, Day: day, CreatedAt: now, UpdatedAt: now, IsCompleted: isCompleted}
 	return habit, nil
 }
 
+func (h *Habit) IsArchived() bool {
+	return h.ArchivedAt != nil
+}
+
+func (h *Habit) IsDeleted() bool {
+	return h.DeletedAt != nil
+}
+
 func (h *Habit) ToggleCompletion() {
 	h.IsCompleted = !h.IsCompleted
+	if h.IsCompleted {
+		h.IsSkipped = false
+	}
+	h.UpdatedAt = time.Now().UTC()
+}
+
+// ToggleSkip skips the habit for the day, a skipped habit is not completed
+func (h *Habit) ToggleSkip() {
+	h.IsSkipped = !h.IsSkipped
+	if h.IsSkipped {
+		h.IsCompleted = false
+	}
 	h.UpdatedAt = time.Now().UTC()
 }
 
@@ -60,15 +95,67 @@ func (h *Habit) ChangeTitle(title string) error {
 	return nil
 }
 
+func (h *Habit) ChangeNote(note string) error {
+	if utf8.RuneCountInString(note) > 1000 {
+		return ErrInvalidHabitNote
+	}
+
+	h.Note = note
+	h.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
 type HabitService interface {
 	// All the habits for heat map
 	HeatMap(ctx context.Context, from time.Time, to time.Time) (map[time.Time]*HeatMap, int, error)
 	// Get all the habits for the given day
 	GetAllByDay(ctx context.Context, day time.Time) (*Chain, error)
+	// Get a habit that is not in the trash
+	GetById(ctx context.Context, id HabitId) (*Habit, error)
+	// Get the habits of the days between from and to, both included, in the
+	// order of the days and the positions
+	ListByRange(ctx context.Context, from time.Time, to time.Time) ([]*Habit, error)
+	// Create the habit, it fails when the habit is archived on its last day
+	// before the new one
 	Create(ctx context.Context, habit *Habit) error
-	// delete a habit
+	// Create the new habits, the ones without an id, and update the others in
+	// one transaction. Nothing is saved when one of them fails
+	Save(ctx context.Context, habits []*Habit) error
+	// Copy the habits to every day as not completed habits in their order. A habit is
+	// not copied to a day that already has it and archived habits are not copied,
+	// neither to the days they are archived on.
+	// Returns the number of created habits
+	Copy(ctx context.Context, ids []HabitId, days []time.Time) (int, error)
+	// move a habit to the trash
 	Delete(ctx context.Context, id HabitId) error
+	// move the habits to the trash at once
+	DeleteMany(ctx context.Context, ids []HabitId) error
+	// Mark the habits as completed or not completed at once
+	SetCompleted(ctx context.Context, ids []HabitId, completed bool) error
+	// Mark the habits as skipped or not skipped at once
+	SetSkipped(ctx context.Context, ids []HabitId, skipped bool) error
+	// Rename the habits, the other habits with their titles are not renamed.
+	// It fails when a day would have two habits with the same title
+	Rename(ctx context.Context, ids []HabitId, title HabitTitle) error
+	// Mark the habit with the title done on every day, it is created on the days
+	// that don't have it unless it is archived. Returns the number of changed days
+	Complete(ctx context.Context, title HabitTitle, days []time.Time) (int, error)
 	Update(ctx context.Context, habit *Habit) error
+	// Move the habit up or down in its day by the offset, the new order is
+	// kept on the following days the habits are tracked together
+	Move(ctx context.Context, id HabitId, offset int) error
+	// Archive the habit on its day and the days before, or unarchive it on
+	// every day it is tracked
+	Archive(ctx context.Context, id HabitId, archived bool) error
+	// Get the deleted habits, the last deleted one is the first
+	Trash(ctx context.Context) ([]*Habit, error)
+	// Restore a habit from the trash
+	Restore(ctx context.Context, id HabitId) error
+	// Permanently delete the habits in the trash, returns the number of deleted habits
+	EmptyTrash(ctx context.Context) (int, error)
+	// Search the titles and the notes of the habits, the best matches and the
+	// latest days are the first
+	Search(ctx context.Context, query string, limit int) ([]*Habit, error)
 }
 
 type Chain struct {
@@ -77,9 +164,13 @@ type Chain struct {
 }
 
 type HeatMap struct {
+	// Number of habits of the day, the skipped habits are not included
 	TotalNumberOfHabits int
 	CompletedHabits     int
-	Day                 int
-	Month               int
-	Year                int
+	SkippedHabits       int
+	// The day is in a vacation, the habits of the day are not counted
+	Vacation bool
+	Day      int
+	Month    int
+	Year     int
 }
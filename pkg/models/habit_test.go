@@ -1,6 +1,7 @@
 package models_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -41,7 +42,8 @@ func TestCreateHabit(t *testing.T) {
 	assert.Equal(t, title, habit.Title)
 	assert.Equal(t, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), habit.Day)
 	assert.Equal(t, isCompleted, habit.IsCompleted)
-	assert.WithinDuration(t, time.Now().UTC(), habit.UpdatedAt, time.Second)
+	assert.WithinDuration(t, time.Now().UTC(), habit.CreatedAt, time.Second)
+	assert.Equal(t, habit.CreatedAt, habit.UpdatedAt)
 }
 
 func TestToggleCompletion(t *testing.T) {
@@ -62,6 +64,23 @@ func TestToggleCompletion(t *testing.T) {
 	})
 }
 
+func TestToggleSkip(t *testing.T) {
+	title := models.HabitTitle("Exercise")
+	habit, _ := models.CreateHabit(title, now, true)
+
+	t.Run("Given completed habit when skipped should clear the completion", func(t *testing.T) {
+		habit.ToggleSkip()
+		assert.True(t, habit.IsSkipped)
+		assert.False(t, habit.IsCompleted)
+	})
+
+	t.Run("Given skipped habit when completed should clear the skip", func(t *testing.T) {
+		habit.ToggleCompletion()
+		assert.True(t, habit.IsCompleted)
+		assert.False(t, habit.IsSkipped)
+	})
+}
+
 func TestChangeTitle(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -89,3 +108,22 @@ func TestChangeTitle(t *testing.T) {
 		})
 	}
 }
+
+func TestChangeNote(t *testing.T) {
+	title := models.HabitTitle("Exercise")
+	habit, _ := models.CreateHabit(title, now, false)
+
+	t.Run("Given note should change it", func(t *testing.T) {
+		err := habit.ChangeNote("Knee hurts")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Knee hurts", habit.Note)
+	})
+
+	t.Run("Given too long note should fail", func(t *testing.T) {
+		err := habit.ChangeNote(strings.Repeat("a", 1001))
+
+		assert.Equal(t, models.ErrInvalidHabitNote, err)
+		assert.Equal(t, "Knee hurts", habit.Note)
+	})
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// Streak is the number of days in a row with at least one completed habit.
+// Vacation days and days where every habit is skipped are neutral, they
+// neither break nor extend a streak.
+type Streak struct {
+	Current int
+	Longest int
+}
+
+type dayStatus int
+
+const (
+	dayFailed dayStatus = iota
+	dayCompleted
+	dayNeutral
+)
+
+func getDayStatus(heatmap *HeatMap) dayStatus {
+	switch {
+	case heatmap == nil:
+		return dayFailed
+	case heatmap.Vacation:
+		return dayNeutral
+	case heatmap.CompletedHabits > 0:
+		return dayCompleted
+	case heatmap.TotalNumberOfHabits == 0 && heatmap.SkippedHabits > 0:
+		return dayNeutral
+	default:
+		return dayFailed
+	}
+}
+
+// CalculateStreak calculates the streaks between the days from and to, both
+// included. The last day does not break the current streak since it may
+// still be completed.
+func CalculateStreak(heatmaps map[time.Time]*HeatMap, from time.Time, to time.Time) Streak {
+	var streak Streak
+	run := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		switch getDayStatus(heatmaps[day]) {
+		case dayCompleted:
+			run++
+			streak.Longest = max(streak.Longest, run)
+		case dayFailed:
+			if !day.Equal(to) {
+				run = 0
+			}
+		}
+	}
+	streak.Current = run
+	return streak
+}
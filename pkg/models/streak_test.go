@@ -0,0 +1,37 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateStreak(t *testing.T) {
+	day := func(d int) time.Time { return utils.CreateDate(2024, 7, d) }
+	completed := &models.HeatMap{TotalNumberOfHabits: 2, CompletedHabits: 1}
+	failed := &models.HeatMap{TotalNumberOfHabits: 2, CompletedHabits: 0}
+	skipped := &models.HeatMap{TotalNumberOfHabits: 0, SkippedHabits: 2}
+	vacation := &models.HeatMap{Vacation: true}
+
+	tests := []struct {
+		name     string
+		heatmaps map[time.Time]*models.HeatMap
+		want     models.Streak
+	}{
+		{"Given completed days should count them", map[time.Time]*models.HeatMap{day(1): completed, day(2): completed, day(3): completed}, models.Streak{Current: 3, Longest: 3}},
+		{"Given failed day should break the streak", map[time.Time]*models.HeatMap{day(1): completed, day(2): failed, day(3): completed}, models.Streak{Current: 1, Longest: 1}},
+		{"Given day without habits should break the streak", map[time.Time]*models.HeatMap{day(1): completed, day(3): completed}, models.Streak{Current: 1, Longest: 1}},
+		{"Given vacation and skipped days should keep the streak", map[time.Time]*models.HeatMap{day(1): completed, day(2): vacation, day(3): skipped}, models.Streak{Current: 1, Longest: 1}},
+		{"Given vacation between completed days should join them", map[time.Time]*models.HeatMap{day(1): completed, day(2): vacation, day(3): completed}, models.Streak{Current: 2, Longest: 2}},
+		{"Given last day not completed yet should keep the current streak", map[time.Time]*models.HeatMap{day(1): failed, day(2): completed, day(3): failed}, models.Streak{Current: 1, Longest: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.CalculateStreak(tt.heatmaps, day(1), day(3)))
+		})
+	}
+}
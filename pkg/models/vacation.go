@@ -0,0 +1,44 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/app"
+	"github.com/metagunner/habheat/pkg/utils"
+)
+
+var ErrInvalidVacation = app.Errorf(app.EINVALID, "Invalid vacation, the vacation must end on or after its first day.")
+
+// Vacation is a range of days that do not count as failures in the heat map
+type Vacation struct {
+	Id        VacationId `json:"id"`
+	From      time.Time  `json:"from"`
+	To        time.Time  `json:"to"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type VacationId int
+
+func CreateVacation(from time.Time, to time.Time) (*Vacation, error) {
+	from = utils.ToDate(from)
+	to = utils.ToDate(to)
+	if to.Before(from) {
+		return nil, ErrInvalidVacation
+	}
+
+	return &Vacation{From: from, To: to, CreatedAt: time.Now().UTC()}, nil
+}
+
+// Returns true if the day is in the vacation, both ends are included
+func (v *Vacation) Contains(day time.Time) bool {
+	day = utils.ToDate(day)
+	return !day.Before(v.From) && !day.After(v.To)
+}
+
+type VacationService interface {
+	// Get all the vacations, the latest one is the first
+	List(ctx context.Context) ([]*Vacation, error)
+	Create(ctx context.Context, vacation *Vacation) error
+	Delete(ctx context.Context, id VacationId) error
+}
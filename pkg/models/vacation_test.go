@@ -0,0 +1,26 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateVacation(t *testing.T) {
+	t.Run("Given range should include both ends", func(t *testing.T) {
+		vacation, err := models.CreateVacation(utils.CreateDate(2024, 7, 1), utils.CreateDate(2024, 7, 3))
+
+		assert.NoError(t, err)
+		assert.True(t, vacation.Contains(utils.CreateDate(2024, 7, 1)))
+		assert.True(t, vacation.Contains(utils.CreateDate(2024, 7, 3)))
+		assert.False(t, vacation.Contains(utils.CreateDate(2024, 7, 4)))
+	})
+
+	t.Run("Given end before start should fail", func(t *testing.T) {
+		_, err := models.CreateVacation(utils.CreateDate(2024, 7, 3), utils.CreateDate(2024, 7, 1))
+
+		assert.Equal(t, models.ErrInvalidVacation, err)
+	})
+}
@@ -0,0 +1,138 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/heatmap"
+	"github.com/metagunner/habheat/pkg/models"
+)
+
+//go:embed report.html
+var reportTemplate string
+
+var tmpl = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format(time.DateOnly) },
+}).Parse(reportTemplate))
+
+// statuses of a habit on a day
+const (
+	StatusDone     = "Done"
+	StatusMissed   = "Missed"
+	StatusSkipped  = "Skipped"
+	StatusVacation = "Vacation"
+)
+
+// Report is the summary of the habits between two days
+type Report struct {
+	From time.Time
+	To   time.Time
+	// the heat map of the days drawn as SVG
+	HeatMap template.HTML
+	Stats   heatmap.Stats
+	// the totals of every habit
+	Total  Summary
+	Habits []*HabitReport
+}
+
+// Summary is the completion count of the habits, the vacation days and the
+// days after today are not counted
+type Summary struct {
+	Tracked   int
+	Completed int
+	Skipped   int
+}
+
+// Returns the percentage of the completed days among the days that are not skipped
+func (s Summary) Percentage() int {
+	if s.Tracked-s.Skipped <= 0 {
+		return 0
+	}
+	return s.Completed * 100 / (s.Tracked - s.Skipped)
+}
+
+func (s *Summary) add(status string) {
+	switch status {
+	case StatusVacation:
+		return
+	case StatusDone:
+		s.Completed++
+	case StatusSkipped:
+		s.Skipped++
+	}
+	s.Tracked++
+}
+
+// HabitReport is a habit with every day it is tracked on
+type HabitReport struct {
+	Title string
+	Summary
+	Days []DayReport
+}
+
+type DayReport struct {
+	Day    time.Time
+	Status string
+	Note   string
+}
+
+// Creates the report of the days between from and to, the habits are in the
+// order they are first tracked
+func New(ctx context.Context, habitService models.HabitService, from time.Time, to time.Time, weekStart time.Weekday, opts heatmap.Options, today time.Time) (*Report, error) {
+	heatmaps, _, err := habitService.HeatMap(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	habits, err := habitService.ListByRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	grid := heatmap.NewGrid(from, to, weekStart, heatmaps, today)
+	var svg bytes.Buffer
+	if err := heatmap.WriteSVG(&svg, grid, opts); err != nil {
+		return nil, err
+	}
+
+	report := &Report{From: grid.From, To: grid.To, HeatMap: template.HTML(svg.String()), Stats: grid.Stats()}
+	byTitle := map[models.HabitTitle]*HabitReport{}
+	for _, habit := range habits {
+		if habit.Day.After(today) {
+			continue
+		}
+		habitReport, ok := byTitle[habit.Title]
+		if !ok {
+			habitReport = &HabitReport{Title: habit.Title.String()}
+			byTitle[habit.Title] = habitReport
+			report.Habits = append(report.Habits, habitReport)
+		}
+
+		status := getStatus(habit, heatmaps[habit.Day])
+		habitReport.add(status)
+		report.Total.add(status)
+		habitReport.Days = append(habitReport.Days, DayReport{Day: habit.Day, Status: status, Note: habit.Note})
+	}
+	return report, nil
+}
+
+func getStatus(habit *models.Habit, day *models.HeatMap) string {
+	switch {
+	case day != nil && day.Vacation:
+		return StatusVacation
+	case habit.IsCompleted:
+		return StatusDone
+	case habit.IsSkipped:
+		return StatusSkipped
+	default:
+		return StatusMissed
+	}
+}
+
+// WriteHTML writes the report as a standalone HTML page
+func (r *Report) WriteHTML(w io.Writer) error {
+	return tmpl.Execute(w, r)
+}
@@ -0,0 +1,91 @@
+package report
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/config"
+	"github.com/metagunner/habheat/pkg/database"
+	"github.com/metagunner/habheat/pkg/heatmap"
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var testDB *database.DB
+
+func TestMain(m *testing.M) {
+	db, err := database.SetupTestDB()
+	if err != nil {
+		log.Fatal(err)
+	}
+	testDB = db
+	code := m.Run()
+	if err := testDB.Close(); err != nil {
+		log.Fatal(err)
+	}
+	os.Exit(code)
+}
+
+func TestReport(t *testing.T) {
+	ctx := context.Background()
+	service := database.NewHabitService(testDB)
+	day := func(d int) time.Time { return utils.CreateDate(2004, 3, d) }
+	create := func(title string, d int, change func(h *models.Habit)) {
+		habitTitle, _ := models.CreateHabitTitle(title)
+		habit, _ := models.CreateHabit(habitTitle, day(d), false)
+		if change != nil {
+			change(habit)
+		}
+		assert.NoError(t, service.Create(ctx, habit))
+	}
+	done := func(h *models.Habit) { h.IsCompleted = true }
+	create("Read", 1, done)
+	create("Run", 1, nil)
+	create("Read", 2, func(h *models.Habit) { h.IsSkipped = true })
+	create("Read", 3, func(h *models.Habit) { h.IsCompleted = true; h.Note = "<b>20 pages</b>" })
+	create("Read", 4, nil)
+	create("Read", 5, done)
+	create("Read", 31, done)
+	vacation, _ := models.CreateVacation(day(5), day(5))
+	assert.NoError(t, database.NewVacationService(testDB).Create(ctx, vacation))
+
+	locale, _ := utils.GetLocale("en")
+	opts := heatmap.DefaultOptions(config.GetDefaultConfig().Gui.Theme.ColorSchemes["green"], locale)
+	report, err := New(ctx, service, day(1), day(31), time.Sunday, opts, day(30))
+	assert.NoError(t, err)
+
+	t.Run("Given habits should summarize them in the order they are first tracked", func(t *testing.T) {
+		assert.Len(t, report.Habits, 2)
+		read := report.Habits[0]
+		assert.Equal(t, "Read", read.Title)
+		// the vacation day and the day after today are not counted
+		assert.Equal(t, Summary{Tracked: 4, Completed: 2, Skipped: 1}, read.Summary)
+		assert.Equal(t, 66, read.Percentage())
+		assert.Equal(t, []string{StatusDone, StatusSkipped, StatusDone, StatusMissed, StatusVacation}, statuses(read))
+		assert.Equal(t, Summary{Tracked: 5, Completed: 2, Skipped: 1}, report.Total)
+	})
+	t.Run("Given report should write it as HTML", func(t *testing.T) {
+		var b strings.Builder
+
+		assert.NoError(t, report.WriteHTML(&b))
+
+		html := b.String()
+		assert.Contains(t, html, "<title>Habits from 2004-03-01 to 2004-03-31</title>")
+		assert.Contains(t, html, `<svg xmlns="http://www.w3.org/2000/svg"`)
+		assert.Contains(t, html, "&lt;b&gt;20 pages&lt;/b&gt;")
+		assert.Contains(t, html, "<td>Read</td>")
+	})
+}
+
+func statuses(habit *HabitReport) []string {
+	result := []string{}
+	for _, day := range habit.Days {
+		result = append(result, day.Status)
+	}
+	return result
+}
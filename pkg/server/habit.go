@@ -0,0 +1,248 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/app"
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/samber/lo"
+)
+
+func (s *Server) registerHabitRoutes() {
+	s.mux.HandleFunc("GET /api/habits", s.handleHabitList)
+	s.mux.HandleFunc("POST /api/habits", s.handleHabitCreate)
+	s.mux.HandleFunc("GET /api/habits/{id}", s.handleHabitView)
+	s.mux.HandleFunc("PATCH /api/habits/{id}", s.handleHabitUpdate)
+	s.mux.HandleFunc("DELETE /api/habits/{id}", s.handleHabitDelete)
+	s.mux.HandleFunc("GET /api/heatmap", s.handleHeatMap)
+	s.mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		Error(w, r, app.Errorf(app.ENOTFOUND, "Not found."))
+	})
+}
+
+type HabitListResponse struct {
+	Day    string          `json:"day"`
+	Habits []*models.Habit `json:"habits"`
+}
+
+// Lists the habits of the day in the "day" query parameter, today by default
+func (s *Server) handleHabitList(w http.ResponseWriter, r *http.Request) {
+	day, err := parseDay(r.URL.Query().Get("day"), utils.Today())
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+
+	chain, err := s.HabitService.GetAllByDay(r.Context(), day)
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &HabitListResponse{Day: chain.Title, Habits: chain.Habits})
+}
+
+func (s *Server) handleHabitView(w http.ResponseWriter, r *http.Request) {
+	id, err := parseHabitId(r)
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+
+	habit, err := s.HabitService.GetById(r.Context(), id)
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, habit)
+}
+
+type HabitCreateRequest struct {
+	Title string `json:"title"`
+	// today when it is empty
+	Day         string `json:"day"`
+	IsCompleted bool   `json:"is_completed"`
+	Note        string `json:"note"`
+}
+
+func (s *Server) handleHabitCreate(w http.ResponseWriter, r *http.Request) {
+	var req HabitCreateRequest
+	if err := readJSON(r, &req); err != nil {
+		Error(w, r, err)
+		return
+	}
+	title, err := models.CreateHabitTitle(req.Title)
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+	day, err := parseDay(req.Day, utils.Today())
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+
+	habit, err := models.CreateHabit(title, day, req.IsCompleted)
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+	if err := habit.ChangeNote(req.Note); err != nil {
+		Error(w, r, err)
+		return
+	}
+	if err := s.HabitService.Create(r.Context(), habit); err != nil {
+		Error(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, habit)
+}
+
+// HabitUpdateRequest changes only the fields that are sent
+type HabitUpdateRequest struct {
+	Title       *string `json:"title"`
+	IsCompleted *bool   `json:"is_completed"`
+	IsSkipped   *bool   `json:"is_skipped"`
+	Note        *string `json:"note"`
+}
+
+func (s *Server) handleHabitUpdate(w http.ResponseWriter, r *http.Request) {
+	id, err := parseHabitId(r)
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+	var req HabitUpdateRequest
+	if err := readJSON(r, &req); err != nil {
+		Error(w, r, err)
+		return
+	}
+	if req.IsCompleted != nil && req.IsSkipped != nil && *req.IsCompleted && *req.IsSkipped {
+		Error(w, r, app.Errorf(app.EINVALID, "A habit can't be both completed and skipped."))
+		return
+	}
+
+	habit, err := s.HabitService.GetById(r.Context(), id)
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+	if req.Title != nil {
+		if err := habit.ChangeTitle(*req.Title); err != nil {
+			Error(w, r, err)
+			return
+		}
+	}
+	if req.IsCompleted != nil && *req.IsCompleted != habit.IsCompleted {
+		habit.ToggleCompletion()
+	}
+	if req.IsSkipped != nil && *req.IsSkipped != habit.IsSkipped {
+		habit.ToggleSkip()
+	}
+	if req.Note != nil {
+		if err := habit.ChangeNote(*req.Note); err != nil {
+			Error(w, r, err)
+			return
+		}
+	}
+
+	if err := s.HabitService.Update(r.Context(), habit); err != nil {
+		Error(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, habit)
+}
+
+// moves the habit to the trash
+func (s *Server) handleHabitDelete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseHabitId(r)
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+
+	if err := s.HabitService.Delete(r.Context(), id); err != nil {
+		Error(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type HeatMapDay struct {
+	Day string `json:"day"`
+	// Number of habits of the day, the skipped habits are not included
+	Total     int  `json:"total"`
+	Completed int  `json:"completed"`
+	Skipped   int  `json:"skipped"`
+	Vacation  bool `json:"vacation"`
+}
+
+type HeatMapResponse struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// only the days with habits or vacations, in order
+	Days []HeatMapDay `json:"days"`
+}
+
+// Returns the heat map between the "from" and "to" query parameters, the last
+// year until today by default
+func (s *Server) handleHeatMap(w http.ResponseWriter, r *http.Request) {
+	to, err := parseDay(r.URL.Query().Get("to"), utils.Today())
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+	from, err := parseDay(r.URL.Query().Get("from"), to.AddDate(-1, 0, 1))
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+	if from.After(to) {
+		Error(w, r, app.Errorf(app.EINVALID, "The from day is after the to day."))
+		return
+	}
+
+	heatMap, _, err := s.HabitService.HeatMap(r.Context(), from, to)
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+
+	days := lo.Keys(heatMap)
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+	resp := &HeatMapResponse{From: from.Format(time.DateOnly), To: to.Format(time.DateOnly), Days: make([]HeatMapDay, 0, len(days))}
+	for _, day := range days {
+		h := heatMap[day]
+		resp.Days = append(resp.Days, HeatMapDay{
+			Day:       day.Format(time.DateOnly),
+			Total:     h.TotalNumberOfHabits,
+			Completed: h.CompletedHabits,
+			Skipped:   h.SkippedHabits,
+			Vacation:  h.Vacation,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// parses a YYYY-MM-DD day, the empty value is the default day
+func parseDay(value string, defaultDay time.Time) (time.Time, error) {
+	if value == "" {
+		return defaultDay, nil
+	}
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, app.Errorf(app.EINVALID, "Invalid day %q, use the YYYY-MM-DD format.", value)
+	}
+	return day, nil
+}
+
+func parseHabitId(r *http.Request) (models.HabitId, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, app.Errorf(app.EINVALID, "Invalid habit id.")
+	}
+	return models.HabitId(id), nil
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/metagunner/habheat/pkg/config"
+	"github.com/metagunner/habheat/pkg/database"
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Habits(t *testing.T) {
+	const day = "2001-03-01"
+	var habit models.Habit
+
+	t.Run("Given habit should create it", func(t *testing.T) {
+		resp := doRequest(t, http.MethodPost, "/api/habits", HabitCreateRequest{Title: "Read", Day: day})
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		habit = decodeResponse[models.Habit](t, resp)
+		assert.NotZero(t, habit.Id)
+		assert.Equal(t, "Read", habit.Title.String())
+	})
+	t.Run("Given empty title should fail", func(t *testing.T) {
+		resp := doRequest(t, http.MethodPost, "/api/habits", HabitCreateRequest{Day: day})
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "Invalid habit title.", decodeResponse[ErrorResponse](t, resp).Error)
+	})
+	t.Run("Given invalid day should fail", func(t *testing.T) {
+		resp := doRequest(t, http.MethodGet, "/api/habits?day=01-03-2001", nil)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+	t.Run("Given day should list its habits", func(t *testing.T) {
+		resp := doRequest(t, http.MethodGet, "/api/habits?day="+day, nil)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		list := decodeResponse[HabitListResponse](t, resp)
+		assert.Equal(t, day, list.Day)
+		assert.Len(t, list.Habits, 1)
+		assert.Equal(t, habit.Id, list.Habits[0].Id)
+	})
+	t.Run("Given changed fields should update only them", func(t *testing.T) {
+		completed, note := true, "20 pages"
+		resp := doRequest(t, http.MethodPatch, fmt.Sprintf("/api/habits/%d", habit.Id), HabitUpdateRequest{IsCompleted: &completed, Note: &note})
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		result := decodeResponse[models.Habit](t, resp)
+		assert.Equal(t, "Read", result.Title.String())
+		assert.True(t, result.IsCompleted)
+		assert.Equal(t, note, result.Note)
+	})
+	t.Run("Given completed and skipped should fail", func(t *testing.T) {
+		yes := true
+		resp := doRequest(t, http.MethodPatch, fmt.Sprintf("/api/habits/%d", habit.Id), HabitUpdateRequest{IsCompleted: &yes, IsSkipped: &yes})
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+	t.Run("Given heat map range should return the days with habits", func(t *testing.T) {
+		resp := doRequest(t, http.MethodGet, "/api/heatmap?from=2001-02-01&to=2001-03-31", nil)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		heatMap := decodeResponse[HeatMapResponse](t, resp)
+		assert.Equal(t, []HeatMapDay{{Day: day, Total: 1, Completed: 1}}, heatMap.Days)
+	})
+	t.Run("Given reversed heat map range should fail", func(t *testing.T) {
+		resp := doRequest(t, http.MethodGet, "/api/heatmap?from=2001-03-31&to=2001-02-01", nil)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+	t.Run("Given habit id should delete it", func(t *testing.T) {
+		resp := doRequest(t, http.MethodDelete, fmt.Sprintf("/api/habits/%d", habit.Id), nil)
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+	t.Run("Given deleted habit id should not find it", func(t *testing.T) {
+		resp := doRequest(t, http.MethodGet, fmt.Sprintf("/api/habits/%d", habit.Id), nil)
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "Habit not found.", decodeResponse[ErrorResponse](t, resp).Error)
+	})
+	t.Run("Given invalid habit id should fail", func(t *testing.T) {
+		resp := doRequest(t, http.MethodDelete, "/api/habits/abc", nil)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestServer_ConcurrentUpdates(t *testing.T) {
+	// a file database like the real one, the requests write from many connections at once
+	db := database.NewDB(filepath.Join(t.TempDir(), "habheat.db"))
+	db.Logger = log.New(io.Discard, "", 0)
+	assert.NoError(t, db.Open())
+	defer db.Close()
+	server := httptest.NewServer(NewServer(database.NewHabitService(db), config.GetDefaultConfig(), testToken))
+	defer server.Close()
+
+	const day = "2001-04-01"
+	ids := []models.HabitId{}
+	for i := 0; i < 10; i++ {
+		resp := doServerRequest(t, server, http.MethodPost, "/api/habits", HabitCreateRequest{Title: fmt.Sprintf("Habit %d", i), Day: day})
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		ids = append(ids, decodeResponse[models.Habit](t, resp).Id)
+	}
+
+	t.Run("Given concurrent updates should apply every one of them", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for _, id := range ids {
+			for j := 0; j < 5; j++ {
+				wg.Add(1)
+				go func(id models.HabitId, j int) {
+					defer wg.Done()
+					completed, note := true, fmt.Sprintf("note %d", j)
+					resp := doServerRequest(t, server, http.MethodPatch, fmt.Sprintf("/api/habits/%d", id), HabitUpdateRequest{IsCompleted: &completed, Note: &note})
+					assert.Equal(t, http.StatusOK, resp.StatusCode)
+				}(id, j)
+			}
+		}
+		wg.Wait()
+
+		resp := doServerRequest(t, server, http.MethodGet, "/api/habits?day="+day, nil)
+		list := decodeResponse[HabitListResponse](t, resp)
+		assert.Len(t, list.Habits, len(ids))
+		for _, habit := range list.Habits {
+			assert.True(t, habit.IsCompleted)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package server
+
+// the error handling is taken from https://github.com/benbjohnson/wtf/blob/main/http/http.go
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/metagunner/habheat/pkg/app"
+	"github.com/metagunner/habheat/pkg/config"
+	"github.com/metagunner/habheat/pkg/models"
+)
+
+var ErrUnauthorized = app.Errorf(app.EUNAUTHORIZED, "Invalid or missing token.")
+
+// Server serves the habits as a JSON API and the web UI. Every API request must
+// send the token in the Authorization header as a bearer token, the files of the
+// web UI are served to everyone.
+type Server struct {
+	HabitService models.HabitService
+	// the week start, the language and the color scheme of the heat map
+	Config *config.UserConfig
+	// the server refuses every API request when it is empty
+	Token string
+	mux   *http.ServeMux
+}
+
+func NewServer(habitService models.HabitService, userConfig *config.UserConfig, token string) *Server {
+	s := &Server{HabitService: habitService, Config: userConfig, Token: token, mux: http.NewServeMux()}
+	s.registerHabitRoutes()
+	s.registerWebRoutes()
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/api/") && !s.authenticate(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="habheat"`)
+		Error(w, r, ErrUnauthorized)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) authenticate(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || s.Token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) == 1
+}
+
+// codes maps the application error codes to the HTTP status codes
+var codes = map[string]int{
+	app.ECONFLICT:       http.StatusConflict,
+	app.EINVALID:        http.StatusBadRequest,
+	app.ENOTFOUND:       http.StatusNotFound,
+	app.ENOTIMPLEMENTED: http.StatusNotImplemented,
+	app.EUNAUTHORIZED:   http.StatusUnauthorized,
+	app.EINTERNAL:       http.StatusInternalServerError,
+}
+
+// ErrorStatusCode returns the HTTP status code of the application error code
+func ErrorStatusCode(code string) int {
+	if v, ok := codes[code]; ok {
+		return v
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorResponse is the body of the failed requests
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Error writes the error as JSON, the internal errors are logged and only
+// "Internal error" is sent to the client
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	code, message := app.ErrorCode(err), app.ErrorMessage(err)
+	if code == app.EINTERNAL {
+		log.Printf("[http] error: %s %s: %s", r.Method, r.URL.Path, err)
+	}
+	writeJSON(w, ErrorStatusCode(code), &ErrorResponse{Error: message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("[http] error: write response: %s", err)
+	}
+}
+
+// decodes the JSON request body into v
+func readJSON(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return app.Errorf(app.EINVALID, "Invalid JSON body.")
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/metagunner/habheat/pkg/app"
+	"github.com/metagunner/habheat/pkg/config"
+	"github.com/metagunner/habheat/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+const testToken = "secret"
+
+var testServer *httptest.Server
+
+func TestMain(m *testing.M) {
+	db, err := database.SetupTestDB()
+	if err != nil {
+		log.Fatal(err)
+	}
+	testServer = httptest.NewServer(NewServer(database.NewHabitService(db), config.GetDefaultConfig(), testToken))
+	code := m.Run()
+	testServer.Close()
+	if err := db.Close(); err != nil {
+		log.Fatal(err)
+	}
+	os.Exit(code)
+}
+
+// sends the request with the test token, the body is encoded as JSON when it is not nil
+func doRequest(t *testing.T, method string, path string, body any) *http.Response {
+	t.Helper()
+	return doServerRequest(t, testServer, method, path, body)
+}
+
+func doServerRequest(t *testing.T, server *httptest.Server, method string, path string, body any) *http.Response {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		assert.NoError(t, err)
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, server.URL+path, reader)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func decodeResponse[T any](t *testing.T, resp *http.Response) T {
+	t.Helper()
+	var v T
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&v))
+	return v
+}
+
+func TestServer_Auth(t *testing.T) {
+	t.Run("Given no token should fail", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + "/api/habits")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, ErrorResponse{Error: "Invalid or missing token."}, decodeResponse[ErrorResponse](t, resp))
+	})
+	t.Run("Given wrong token should fail", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, testServer.URL+"/api/habits", nil)
+		req.Header.Set("Authorization", "Bearer wrong")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+	t.Run("Given empty server token should refuse every request", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/habits", nil)
+		req.Header.Set("Authorization", "Bearer ")
+
+		NewServer(nil, config.GetDefaultConfig(), "").ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		err     error
+		status  int
+		message string
+	}{
+		{app.Errorf(app.EINVALID, "Invalid habit title."), http.StatusBadRequest, "Invalid habit title."},
+		{app.Errorf(app.ENOTFOUND, "Habit not found."), http.StatusNotFound, "Habit not found."},
+		{app.Errorf(app.ECONFLICT, "Conflict."), http.StatusConflict, "Conflict."},
+		{app.Errorf(app.ENOTIMPLEMENTED, "Not implemented."), http.StatusNotImplemented, "Not implemented."},
+		{io.ErrUnexpectedEOF, http.StatusInternalServerError, "Internal error."},
+	}
+	for _, tt := range tests {
+		t.Run("Given "+app.ErrorCode(tt.err)+" error should write its status", func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			Error(rec, httptest.NewRequest(http.MethodGet, "/", nil), tt.err)
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.JSONEq(t, `{"error": "`+tt.message+`"}`, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"strconv"
+
+	"github.com/metagunner/habheat/pkg/app"
+	"github.com/metagunner/habheat/pkg/heatmap"
+	"github.com/metagunner/habheat/pkg/utils"
+)
+
+//go:embed web
+var webFiles embed.FS
+
+func (s *Server) registerWebRoutes() {
+	files, _ := fs.Sub(webFiles, "web")
+	s.mux.Handle("/", http.FileServerFS(files))
+	s.mux.HandleFunc("GET /api/heatmap.svg", s.handleHeatMapSVG)
+}
+
+// Draws the heat map of the year in the "year" query parameter like the
+// terminal, the last year by default
+func (s *Server) handleHeatMapSVG(w http.ResponseWriter, r *http.Request) {
+	weekStart := s.Config.Gui.GetWeekStart()
+	from, to := heatmap.LastYearRange(utils.Today(), weekStart)
+	if value := r.URL.Query().Get("year"); value != "" {
+		year, err := strconv.Atoi(value)
+		if err != nil || year < 1 || year > 9999 {
+			Error(w, r, app.Errorf(app.EINVALID, "Invalid year %q.", value))
+			return
+		}
+		from, to = heatmap.YearRange(year)
+	}
+
+	heatmaps, _, err := s.HabitService.HeatMap(r.Context(), from, to)
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+
+	theme := s.Config.Gui.Theme
+	locale, _ := utils.GetLocale(s.Config.Gui.Language)
+	grid := heatmap.NewGrid(from, to, weekStart, heatmaps, utils.Today())
+	w.Header().Set("Content-Type", "image/svg+xml")
+	if err := heatmap.WriteSVG(w, grid, heatmap.DefaultOptions(theme.ColorSchemes[theme.Selected], locale)); err != nil {
+		Error(w, r, err)
+	}
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Web(t *testing.T) {
+	t.Run("Given no token should serve the web UI", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + "/")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Contains(t, string(body), "<title>Habheat</title>")
+	})
+	t.Run("Given year should draw its heat map", func(t *testing.T) {
+		doRequest(t, http.MethodPost, "/api/habits", HabitCreateRequest{Title: "Read", Day: "2002-05-01", IsCompleted: true})
+
+		resp := doRequest(t, http.MethodGet, "/api/heatmap.svg?year=2002", nil)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "image/svg+xml", resp.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(resp.Body)
+		assert.Contains(t, string(body), `data-day="2002-05-01"><title>1/1 habits on May 1st</title>`)
+		assert.Contains(t, string(body), `data-day="2002-12-31"`)
+		assert.NotContains(t, string(body), `data-day="2001-12-31"`)
+	})
+	t.Run("Given invalid year should fail", func(t *testing.T) {
+		resp := doRequest(t, http.MethodGet, "/api/heatmap.svg?year=abc", nil)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+	t.Run("Given no token should not draw the heat map", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + "/api/heatmap.svg")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -0,0 +1,230 @@
+package todotxt
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/metagunner/habheat/pkg/models"
+)
+
+// tag of the skipped habits, e.g. "2024-07-08 Run +habit skipped:true"
+const skippedTag = "skipped"
+
+// Converts the habit to a task. A done habit is completed on its day, the
+// other habits are created on their day. The projects, the contexts and the
+// tags kept in the note are added after the title, the project is added unless
+// it is empty or the task already has it.
+func FromHabit(habit *models.Habit, project string) *Task {
+	task := &Task{Completed: habit.IsCompleted, Description: habit.Title.String()}
+	for _, tag := range noteTags(habit.Note) {
+		task.Description += " " + tag
+	}
+	if project != "" && !task.HasProject(project) {
+		task.Description += " +" + project
+	}
+	if habit.IsSkipped {
+		task.Description += " " + skippedTag + ":true"
+	}
+	if habit.IsCompleted {
+		task.CompletionDate = habit.Day
+	} else {
+		task.CreationDate = habit.Day
+	}
+	return task
+}
+
+// Converts the task to a habit of its day, today when the task doesn't have a
+// date. The project and the skipped tag are removed from the title. Habheat
+// doesn't have tags, the other projects, the contexts and the tags are kept in
+// the note so FromHabit adds them back.
+func ToHabit(task *Task, project string, today time.Time) (*models.Habit, error) {
+	words, tags := []string{}, []string{}
+	for _, word := range strings.Fields(task.Description) {
+		if project != "" && word == "+"+project {
+			continue
+		}
+		if key, _, ok := parseTag(word); ok && key == skippedTag {
+			continue
+		}
+		if isNoteTag(word) {
+			tags = append(tags, word)
+			continue
+		}
+		words = append(words, word)
+	}
+	title, err := models.CreateHabitTitle(strings.Join(words, " "))
+	if err != nil {
+		return nil, err
+	}
+
+	habit, err := models.CreateHabit(title, getDay(task, today), task.Completed)
+	if err != nil {
+		return nil, err
+	}
+	habit.IsSkipped = !task.Completed && task.Tags()[skippedTag] == "true"
+	if err := habit.ChangeNote(strings.Join(tags, " ")); err != nil {
+		return nil, err
+	}
+	return habit, nil
+}
+
+// the projects, the contexts and the tags are kept in the notes, e.g. +habit,
+// @health or due:2024-07-09
+func isNoteTag(word string) bool {
+	if key, _, ok := parseTag(word); ok {
+		return key != skippedTag
+	}
+	return len(word) > 1 && (word[0] == '+' || word[0] == '@')
+}
+
+// Returns the projects, the contexts and the tags of the note
+func noteTags(note string) []string {
+	tags := []string{}
+	for _, word := range strings.Fields(note) {
+		if isNoteTag(word) {
+			tags = append(tags, word)
+		}
+	}
+	return tags
+}
+
+// Returns the note with the tags in place of its old tags, the other words of
+// the note are kept
+func replaceNoteTags(note string, tags []string) string {
+	words := []string{}
+	for _, word := range strings.Fields(note) {
+		if !isNoteTag(word) {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(append(words, tags...), " ")
+}
+
+func getDay(task *Task, today time.Time) time.Time {
+	switch {
+	case task.Completed && !task.CompletionDate.IsZero():
+		return task.CompletionDate
+	case !task.CreationDate.IsZero():
+		return task.CreationDate
+	default:
+		return today
+	}
+}
+
+// Returns the habits of the days between from and to as tasks
+func Export(ctx context.Context, habitService models.HabitService, from time.Time, to time.Time, project string) ([]*Task, error) {
+	habits, err := habitService.ListByRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]*Task, 0, len(habits))
+	for _, habit := range habits {
+		tasks = append(tasks, FromHabit(habit, project))
+	}
+	return tasks, nil
+}
+
+// TaskError is the error of a task that can't be imported
+type TaskError struct {
+	Task *Task
+	Err  error
+}
+
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("%q: %s", e.Task.String(), e.Err)
+}
+
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+type ImportResult struct {
+	Created int
+	Updated int
+	// tasks without the project
+	Ignored int
+}
+
+// Imports the tasks with the project, every task when the project is empty. A
+// task updates the habit with the same title on its day, the habit is created
+// when the day doesn't have it. The tags of the task replace the tags in the
+// note of the habit. Every task is checked before the habits are saved in one
+// transaction, nothing is imported when a task is invalid and the error is a
+// TaskError.
+func Import(ctx context.Context, habitService models.HabitService, tasks []*Task, project string, today time.Time) (*ImportResult, error) {
+	result := &ImportResult{}
+	chains := map[time.Time]*models.Chain{}
+	changed := []*models.Habit{}
+	for _, task := range tasks {
+		if project != "" && !task.HasProject(project) {
+			result.Ignored++
+			continue
+		}
+		imported, err := ToHabit(task, project, today)
+		if err != nil {
+			return nil, &TaskError{Task: task, Err: err}
+		}
+
+		chain, ok := chains[imported.Day]
+		if !ok {
+			if chain, err = habitService.GetAllByDay(ctx, imported.Day); err != nil {
+				return nil, err
+			}
+			chains[imported.Day] = chain
+		}
+
+		habit := findHabit(chain, imported.Title)
+		if habit == nil {
+			chain.Habits = append(chain.Habits, imported)
+			changed = append(changed, imported)
+			result.Created++
+			continue
+		}
+		updated, err := updateHabit(habit, imported)
+		if err != nil {
+			return nil, &TaskError{Task: task, Err: err}
+		}
+		// a habit created by an earlier task is already saved with the change
+		if updated && !slices.Contains(changed, habit) {
+			changed = append(changed, habit)
+			result.Updated++
+		}
+	}
+
+	if err := habitService.Save(ctx, changed); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Changes the habit like the imported habit, returns whether it is changed
+func updateHabit(habit *models.Habit, imported *models.Habit) (bool, error) {
+	updated := false
+	if habit.IsCompleted != imported.IsCompleted {
+		habit.ToggleCompletion()
+		updated = true
+	}
+	if habit.IsSkipped != imported.IsSkipped {
+		habit.ToggleSkip()
+		updated = true
+	}
+	if tags := noteTags(imported.Note); !slices.Equal(noteTags(habit.Note), tags) {
+		if err := habit.ChangeNote(replaceNoteTags(habit.Note, tags)); err != nil {
+			return false, err
+		}
+		updated = true
+	}
+	return updated, nil
+}
+
+func findHabit(chain *models.Chain, title models.HabitTitle) *models.Habit {
+	for _, habit := range chain.Habits {
+		if habit.Title == title {
+			return habit
+		}
+	}
+	return nil
+}
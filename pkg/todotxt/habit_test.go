@@ -0,0 +1,170 @@
+package todotxt
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/metagunner/habheat/pkg/app"
+	"github.com/metagunner/habheat/pkg/database"
+	"github.com/metagunner/habheat/pkg/models"
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var testDB *database.DB
+
+func TestMain(m *testing.M) {
+	db, err := database.SetupTestDB()
+	if err != nil {
+		log.Fatal(err)
+	}
+	testDB = db
+	code := m.Run()
+	if err := testDB.Close(); err != nil {
+		log.Fatal(err)
+	}
+	os.Exit(code)
+}
+
+func TestFromHabit(t *testing.T) {
+	day := utils.CreateDate(2024, 7, 8)
+	create := func(title string, change func(h *models.Habit)) *models.Habit {
+		habitTitle, _ := models.CreateHabitTitle(title)
+		habit, _ := models.CreateHabit(habitTitle, day, false)
+		if change != nil {
+			change(habit)
+		}
+		return habit
+	}
+
+	assert.Equal(t, "x 2024-07-08 Read 20 pages @health +habit", FromHabit(create("Read 20 pages @health", func(h *models.Habit) { h.IsCompleted = true }), "habit").String())
+	assert.Equal(t, "2024-07-08 Run +habit skipped:true", FromHabit(create("Run", func(h *models.Habit) { h.IsSkipped = true }), "habit").String())
+	assert.Equal(t, "2024-07-08 Meditate +habit", FromHabit(create("Meditate +habit", nil), "habit").String())
+	assert.Equal(t, "2024-07-08 Meditate", FromHabit(create("Meditate", nil), "").String())
+	assert.Equal(t, "2024-07-08 Walk @park due:2024-07-09 +habit", FromHabit(create("Walk", func(h *models.Habit) { h.Note = "@park due:2024-07-09 with the dog" }), "habit").String())
+}
+
+func TestToHabit(t *testing.T) {
+	today := utils.CreateDate(2024, 7, 10)
+
+	t.Run("Given tasks should convert them to the habits of their days", func(t *testing.T) {
+		task, _ := Parse("x 2024-07-08 Read 20 pages +habit @health")
+		habit, err := ToHabit(task, "habit", today)
+		assert.NoError(t, err)
+		assert.Equal(t, models.HabitTitle("Read 20 pages"), habit.Title)
+		assert.Equal(t, "@health", habit.Note)
+		assert.Equal(t, utils.CreateDate(2024, 7, 8), habit.Day)
+		assert.True(t, habit.IsCompleted)
+
+		task, _ = Parse("Run +habit skipped:true")
+		habit, err = ToHabit(task, "habit", today)
+		assert.NoError(t, err)
+		assert.Equal(t, models.HabitTitle("Run"), habit.Title)
+		assert.Equal(t, today, habit.Day)
+		assert.True(t, habit.IsSkipped)
+	})
+
+	t.Run("Given projects, contexts and tags should keep them in the note", func(t *testing.T) {
+		task, _ := Parse("2024-07-08 Walk @park +fitness +habit due:2024-07-09 skipped:true")
+		habit, err := ToHabit(task, "habit", today)
+		assert.NoError(t, err)
+		assert.Equal(t, models.HabitTitle("Walk"), habit.Title)
+		assert.Equal(t, "@park +fitness due:2024-07-09", habit.Note)
+
+		assert.Equal(t, "2024-07-08 Walk @park +fitness due:2024-07-09 +habit skipped:true", FromHabit(habit, "habit").String())
+	})
+
+	t.Run("Given task with only the project should fail", func(t *testing.T) {
+		task, _ := Parse("2024-07-08 +habit")
+
+		_, err := ToHabit(task, "habit", today)
+
+		assert.Equal(t, app.EINVALID, app.ErrorCode(err))
+	})
+}
+
+func TestImportExport(t *testing.T) {
+	ctx := context.Background()
+	service := database.NewHabitService(testDB)
+	from, to := utils.CreateDate(2006, 3, 1), utils.CreateDate(2006, 3, 31)
+	habitTitle, _ := models.CreateHabitTitle("Read")
+	habit, _ := models.CreateHabit(habitTitle, utils.CreateDate(2006, 3, 2), false)
+	assert.NoError(t, service.Create(ctx, habit))
+
+	t.Run("Given tasks should create and update the habits of the project", func(t *testing.T) {
+		content := "x 2006-03-02 Read +habit\n2006-03-02 Run +habit skipped:true\n2006-03-03 Call mom\nx 2006-03-03 Run @gym +habit\n"
+		tasks, err := Read(strings.NewReader(content))
+		assert.NoError(t, err)
+
+		result, err := Import(ctx, service, tasks, "habit", to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &ImportResult{Created: 2, Updated: 1, Ignored: 1}, result)
+	})
+
+	t.Run("Given same tasks again should not change the habits", func(t *testing.T) {
+		tasks, _ := Read(strings.NewReader("x 2006-03-02 Read +habit\n2006-03-02 Run +habit skipped:true\n"))
+
+		result, err := Import(ctx, service, tasks, "habit", to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &ImportResult{}, result)
+	})
+
+	t.Run("Given habits should export them in the order of the days", func(t *testing.T) {
+		tasks, err := Export(ctx, service, from, to, "habit")
+		assert.NoError(t, err)
+		var b bytes.Buffer
+		assert.NoError(t, Write(&b, tasks))
+
+		assert.Equal(t, "x 2006-03-02 Read +habit\n2006-03-02 Run +habit skipped:true\nx 2006-03-03 Run @gym +habit\n", b.String())
+	})
+
+	t.Run("Given changed tags should replace the tags in the note", func(t *testing.T) {
+		chain, err := service.GetAllByDay(ctx, utils.CreateDate(2006, 3, 3))
+		assert.NoError(t, err)
+		run := chain.Habits[0]
+		assert.NoError(t, run.ChangeNote("after work @gym"))
+		assert.NoError(t, service.Update(ctx, run))
+		tasks, _ := Read(strings.NewReader("x 2006-03-03 Run @park +habit\n"))
+
+		result, err := Import(ctx, service, tasks, "habit", to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &ImportResult{Updated: 1}, result)
+		chain, err = service.GetAllByDay(ctx, utils.CreateDate(2006, 3, 3))
+		assert.NoError(t, err)
+		assert.Equal(t, "after work @park", chain.Habits[0].Note)
+	})
+
+	t.Run("Given same habit twice should create it once", func(t *testing.T) {
+		tasks, _ := Read(strings.NewReader("2006-03-05 Swim +habit\nx 2006-03-05 Swim +habit\n"))
+
+		result, err := Import(ctx, service, tasks, "habit", to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &ImportResult{Created: 1}, result)
+		chain, err := service.GetAllByDay(ctx, utils.CreateDate(2006, 3, 5))
+		assert.NoError(t, err)
+		assert.Len(t, chain.Habits, 1)
+		assert.True(t, chain.Habits[0].IsCompleted)
+	})
+
+	t.Run("Given invalid task should import nothing", func(t *testing.T) {
+		tasks, _ := Read(strings.NewReader("2006-03-04 Walk +habit\n2006-03-04 @park +habit\n"))
+
+		_, err := Import(ctx, service, tasks, "habit", to)
+
+		var taskErr *TaskError
+		assert.ErrorAs(t, err, &taskErr)
+		assert.Equal(t, "2006-03-04 @park +habit", taskErr.Task.String())
+		assert.ErrorIs(t, err, models.ErrInvalidHabitTitle)
+		chain, err := service.GetAllByDay(ctx, utils.CreateDate(2006, 3, 4))
+		assert.NoError(t, err)
+		assert.Empty(t, chain.Habits)
+	})
+}
@@ -0,0 +1,158 @@
+package todotxt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Task is a line of a todo.txt file, e.g. "x 2024-07-08 Read 20 pages +habit @health"
+type Task struct {
+	Completed bool
+	// A to Z, zero when the task doesn't have a priority
+	Priority       byte
+	CompletionDate time.Time
+	CreationDate   time.Time
+	// the text after the dates with the projects, the contexts and the tags
+	Description string
+}
+
+var priorityRegexp = regexp.MustCompile(`^\(([A-Z])\) `)
+
+// Parses a line of a todo.txt file. A completed task with one date has the
+// completion date, e.g. "x 2024-07-08 Read".
+func Parse(line string) (*Task, error) {
+	rest := strings.TrimSpace(line)
+	task := &Task{}
+	if strings.HasPrefix(rest, "x ") {
+		task.Completed = true
+		rest = strings.TrimLeft(rest[2:], " ")
+	}
+	if match := priorityRegexp.FindStringSubmatch(rest); match != nil {
+		task.Priority = match[1][0]
+		rest = rest[len(match[0]):]
+	}
+
+	dates := []time.Time{}
+	for len(dates) < 2 {
+		word, after, _ := strings.Cut(rest, " ")
+		date, err := time.Parse(time.DateOnly, word)
+		if err != nil {
+			break
+		}
+		dates = append(dates, date)
+		rest = strings.TrimLeft(after, " ")
+	}
+	switch {
+	case len(dates) == 2:
+		task.CompletionDate, task.CreationDate = dates[0], dates[1]
+	case len(dates) == 1 && task.Completed:
+		task.CompletionDate = dates[0]
+	case len(dates) == 1:
+		task.CreationDate = dates[0]
+	}
+	if !task.Completed && !task.CompletionDate.IsZero() {
+		return nil, fmt.Errorf("%q has a completion date but it is not completed", line)
+	}
+
+	task.Description = rest
+	if task.Description == "" {
+		return nil, fmt.Errorf("%q doesn't have a description", line)
+	}
+	return task, nil
+}
+
+func (t *Task) String() string {
+	parts := []string{}
+	if t.Completed {
+		parts = append(parts, "x")
+	}
+	if t.Priority != 0 {
+		parts = append(parts, "("+string(t.Priority)+")")
+	}
+	if !t.CompletionDate.IsZero() {
+		parts = append(parts, t.CompletionDate.Format(time.DateOnly))
+	}
+	if !t.CreationDate.IsZero() {
+		parts = append(parts, t.CreationDate.Format(time.DateOnly))
+	}
+	return strings.Join(append(parts, t.Description), " ")
+}
+
+// Returns the projects of the task without the +, e.g. "habit" for +habit
+func (t *Task) Projects() []string {
+	return t.words("+")
+}
+
+// Returns the contexts of the task without the @, e.g. "health" for @health
+func (t *Task) Contexts() []string {
+	return t.words("@")
+}
+
+// Returns the key:value tags of the task, e.g. due:2024-07-08
+func (t *Task) Tags() map[string]string {
+	tags := map[string]string{}
+	for _, word := range strings.Fields(t.Description) {
+		if key, value, ok := parseTag(word); ok {
+			tags[key] = value
+		}
+	}
+	return tags
+}
+
+func (t *Task) HasProject(project string) bool {
+	for _, p := range t.Projects() {
+		if p == project {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Task) words(prefix string) []string {
+	words := []string{}
+	for _, word := range strings.Fields(t.Description) {
+		if len(word) > 1 && strings.HasPrefix(word, prefix) {
+			words = append(words, word[1:])
+		}
+	}
+	return words
+}
+
+// a tag is a key:value word, URLs like https://example.com are not tags
+func parseTag(word string) (string, string, bool) {
+	key, value, ok := strings.Cut(word, ":")
+	if !ok || key == "" || value == "" || strings.ContainsAny(key, "+@") || strings.HasPrefix(value, "/") {
+		return "", "", false
+	}
+	return key, value, true
+}
+
+// Reads the tasks of a todo.txt file, the blank lines are skipped
+func Read(r io.Reader) ([]*Task, error) {
+	tasks := []*Task{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		task, err := Parse(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, scanner.Err()
+}
+
+// Writes the tasks as the lines of a todo.txt file
+func Write(w io.Writer, tasks []*Task) error {
+	b := bufio.NewWriter(w)
+	for _, task := range tasks {
+		fmt.Fprintln(b, task.String())
+	}
+	return b.Flush()
+}
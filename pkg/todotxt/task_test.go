@@ -0,0 +1,85 @@
+package todotxt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/metagunner/habheat/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Given completed task with one date should parse the completion date", func(t *testing.T) {
+		task, err := Parse("x 2024-07-08 Read 20 pages +habit @health")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Task{Completed: true, CompletionDate: utils.CreateDate(2024, 7, 8), Description: "Read 20 pages +habit @health"}, task)
+		assert.Equal(t, []string{"habit"}, task.Projects())
+		assert.Equal(t, []string{"health"}, task.Contexts())
+	})
+
+	t.Run("Given task with priority and dates should parse them", func(t *testing.T) {
+		task, err := Parse("x (A) 2024-07-09 2024-07-01 Call mom due:2024-07-10 https://example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Task{
+			Completed:      true,
+			Priority:       'A',
+			CompletionDate: utils.CreateDate(2024, 7, 9),
+			CreationDate:   utils.CreateDate(2024, 7, 1),
+			Description:    "Call mom due:2024-07-10 https://example.com",
+		}, task)
+		assert.Equal(t, map[string]string{"due": "2024-07-10"}, task.Tags())
+	})
+
+	t.Run("Given open task with one date should parse the creation date", func(t *testing.T) {
+		task, err := Parse("2024-07-08 Run +habit")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Task{CreationDate: utils.CreateDate(2024, 7, 8), Description: "Run +habit"}, task)
+	})
+
+	t.Run("Given invalid lines should fail", func(t *testing.T) {
+		_, err := Parse("2024-07-08 2024-07-01 Run")
+		assert.Error(t, err)
+
+		_, err = Parse("x 2024-07-08")
+		assert.Error(t, err)
+	})
+}
+
+func TestTask_String(t *testing.T) {
+	lines := []string{
+		"x 2024-07-08 Read 20 pages +habit @health",
+		"x (A) 2024-07-09 2024-07-01 Call mom",
+		"(B) 2024-07-08 Run +habit skipped:true",
+		"Meditate",
+	}
+	for _, line := range lines {
+		task, err := Parse(line)
+
+		assert.NoError(t, err)
+		assert.Equal(t, line, task.String())
+	}
+}
+
+func TestRead(t *testing.T) {
+	t.Run("Given file should skip the blank lines and write the same tasks", func(t *testing.T) {
+		content := "x 2024-07-08 Read +habit\n\n2024-07-08 Run +habit\n"
+
+		tasks, err := Read(strings.NewReader(content))
+		assert.NoError(t, err)
+		var b bytes.Buffer
+		assert.NoError(t, Write(&b, tasks))
+
+		assert.Len(t, tasks, 2)
+		assert.Equal(t, "x 2024-07-08 Read +habit\n2024-07-08 Run +habit\n", b.String())
+	})
+
+	t.Run("Given invalid line should report its number", func(t *testing.T) {
+		_, err := Read(strings.NewReader("Read\n\n2024-07-08 2024-07-01 Run\n"))
+
+		assert.ErrorContains(t, err, "line 3:")
+	})
+}
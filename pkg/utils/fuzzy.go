@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FuzzyScore matches the pattern against the text as a case insensitive
+// subsequence, the spaces in the pattern are ignored. Consecutive letters and
+// letters at the start of a word score higher. Returns false when the text
+// doesn't contain every letter of the pattern in order.
+func FuzzyScore(pattern string, text string) (int, bool) {
+	patternRunes := []rune(strings.ToLower(strings.Join(strings.Fields(pattern), "")))
+	if len(patternRunes) == 0 {
+		return 0, false
+	}
+
+	textRunes := []rune(strings.ToLower(text))
+	score := 0
+	matched := 0
+	previous := -2
+	for i, r := range textRunes {
+		if matched == len(patternRunes) {
+			break
+		}
+		if r != patternRunes[matched] {
+			continue
+		}
+
+		score++
+		if previous == i-1 {
+			score += 5
+		}
+		if i == 0 || !(unicode.IsLetter(textRunes[i-1]) || unicode.IsDigit(textRunes[i-1])) {
+			score += 3
+		}
+		previous = i
+		matched++
+	}
+	if matched < len(patternRunes) {
+		return 0, false
+	}
+
+	return score, true
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	t.Run("Given letters in order should match", func(t *testing.T) {
+		_, ok := FuzzyScore("dnt fls", "Dentist floss")
+
+		assert.True(t, ok)
+	})
+
+	t.Run("Given letters out of order should not match", func(t *testing.T) {
+		_, ok := FuzzyScore("flossdentist", "Dentist floss")
+
+		assert.False(t, ok)
+	})
+
+	t.Run("Given empty pattern should not match", func(t *testing.T) {
+		_, ok := FuzzyScore("  ", "Dentist floss")
+
+		assert.False(t, ok)
+	})
+
+	t.Run("Given consecutive letters should score higher", func(t *testing.T) {
+		exact, _ := FuzzyScore("floss", "Dentist floss")
+		scattered, _ := FuzzyScore("floss", "Follow a strict schedule")
+
+		assert.Greater(t, exact, scattered)
+	})
+}
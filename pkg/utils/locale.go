@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Locale has the month and weekday names of a language.
+type Locale struct {
+	Language string
+	// short month names starting from January
+	Months [12]string
+	// short weekday names starting from Sunday
+	Weekdays [7]string
+	// prints the day of the month with its ordinal suffix
+	ordinal func(day int) string
+	// whether the day is printed before the month, e.g. "8. Jul"
+	dayFirst bool
+}
+
+var locales = map[string]Locale{
+	"en": {
+		Language: "en",
+		Months:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		Weekdays: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		ordinal:  GetOrdinalSuffix,
+	},
+	"de": {
+		Language: "de",
+		Months:   [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		Weekdays: [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		ordinal:  func(day int) string { return fmt.Sprintf("%d.", day) },
+		dayFirst: true,
+	},
+	"es": {
+		Language: "es",
+		Months:   [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sep", "oct", "nov", "dic"},
+		Weekdays: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		ordinal:  func(day int) string { return fmt.Sprintf("%d", day) },
+		dayFirst: true,
+	},
+	"fr": {
+		Language: "fr",
+		Months:   [12]string{"jan", "fév", "mar", "avr", "mai", "juin", "juil", "aoû", "sep", "oct", "nov", "déc"},
+		Weekdays: [7]string{"dim", "lun", "mar", "mer", "jeu", "ven", "sam"},
+		ordinal: func(day int) string {
+			if day == 1 {
+				return "1er"
+			}
+			return fmt.Sprintf("%d", day)
+		},
+		dayFirst: true,
+	},
+	"tr": {
+		Language: "tr",
+		Months:   [12]string{"Oca", "Şub", "Mar", "Nis", "May", "Haz", "Tem", "Ağu", "Eyl", "Eki", "Kas", "Ara"},
+		Weekdays: [7]string{"Paz", "Pzt", "Sal", "Çar", "Per", "Cum", "Cmt"},
+		ordinal:  func(day int) string { return fmt.Sprintf("%d", day) },
+		dayFirst: true,
+	},
+}
+
+// GetLocale returns the locale of the given language code, e.g. "en".
+func GetLocale(language string) (Locale, bool) {
+	locale, ok := locales[language]
+	return locale, ok
+}
+
+// GetLanguages returns the codes of the supported languages.
+func GetLanguages() []string {
+	languages := make([]string, 0, len(locales))
+	for language := range locales {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+func (l Locale) MonthName(month time.Month) string {
+	return l.Months[month-1]
+}
+
+func (l Locale) WeekdayName(weekday time.Weekday) string {
+	return l.Weekdays[weekday]
+}
+
+// Returns the day of the month with its ordinal suffix, e.g. "8th" in English
+func (l Locale) Ordinal(day int) string {
+	return l.ordinal(day)
+}
+
+// Returns the month and the day in the order of the language, e.g. "Jul 8th" or "8. Jul"
+func (l Locale) FormatMonthDay(t time.Time) string {
+	if l.dayFirst {
+		return fmt.Sprintf("%s %s", l.Ordinal(t.Day()), l.MonthName(t.Month()))
+	}
+	return fmt.Sprintf("%s %s", l.MonthName(t.Month()), l.Ordinal(t.Day()))
+}
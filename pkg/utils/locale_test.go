@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocale_FormatMonthDay(t *testing.T) {
+	date := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		language string
+		want     string
+	}{
+		{"en", "Jul 1st"},
+		{"de", "1. Jul"},
+		{"fr", "1er juil"},
+		{"tr", "1 Tem"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			locale, ok := GetLocale(tt.language)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, locale.FormatMonthDay(date))
+		})
+	}
+}
+
+func TestGetLocale(t *testing.T) {
+	locale, ok := GetLocale("de")
+	assert.True(t, ok)
+	assert.Equal(t, "Mi", locale.WeekdayName(time.Wednesday))
+	assert.Equal(t, "Mär", locale.MonthName(time.March))
+
+	_, ok = GetLocale("xx")
+	assert.False(t, ok)
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 )
 
@@ -34,6 +35,8 @@ func GetDaysInMonth(t time.Time) int {
 	return lastDayOfMonth.Day()
 }
 
+// Creates a calendar day, the days are always kept at midnight UTC so they can
+// be compared and used as map keys
 func CreateDate(year int, month time.Month, day int) time.Time {
 	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
 }
@@ -55,3 +58,85 @@ func GetOrdinalSuffix(day int) string {
 		return fmt.Sprintf("%dth", day)
 	}
 }
+
+// Returns the first day of the week on or before the given day
+func GetStartOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	offset := (int(t.Weekday()) - int(weekStart) + 7) % 7
+	return t.AddDate(0, 0, -offset)
+}
+
+// DayClock decides which calendar day an instant belongs to. Days are stored
+// as dates at midnight UTC, see CreateDate.
+type DayClock struct {
+	// time zone of the days
+	Location *time.Location
+	// hour the day starts at, the instants before it belong to the previous day
+	StartHour int
+}
+
+var dayClock = DayClock{Location: time.Local}
+
+// SetDayClock sets the clock used by Today and DayOf
+func SetDayClock(clock DayClock) {
+	if clock.Location == nil {
+		clock.Location = time.Local
+	}
+	dayClock = clock
+}
+
+// DayOf returns the calendar day the instant belongs to
+func (c DayClock) DayOf(t time.Time) time.Time {
+	t = t.In(c.Location).Add(-time.Duration(c.StartHour) * time.Hour)
+	return CreateDate(t.Year(), t.Month(), t.Day())
+}
+
+// Returns the calendar day the instant belongs to using the configured clock
+func DayOf(t time.Time) time.Time {
+	return dayClock.DayOf(t)
+}
+
+// Returns the current calendar day using the configured clock
+func Today() time.Time {
+	return dayClock.DayOf(time.Now())
+}
+
+// Returns the date of the given time as a calendar day, the time of the day
+// and the location are dropped without converting the time
+func ToDate(t time.Time) time.Time {
+	return CreateDate(t.Year(), t.Month(), t.Day())
+}
+
+// the longest range of days ParseDays accepts
+const maxDays = 366
+
+// Parses a day like 2024-07-01 or a range of days like 2024-07-01 2024-07-14
+// and returns every day of it
+func ParseDays(s string) ([]time.Time, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("invalid days %q, use YYYY-MM-DD or YYYY-MM-DD YYYY-MM-DD", s)
+	}
+
+	from, err := time.Parse(time.DateOnly, fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid day %q, use the YYYY-MM-DD format", fields[0])
+	}
+	to := from
+	if len(fields) == 2 {
+		if to, err = time.Parse(time.DateOnly, fields[1]); err != nil {
+			return nil, fmt.Errorf("invalid day %q, use the YYYY-MM-DD format", fields[1])
+		}
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("the range ends on %s before it starts", fields[1])
+	}
+	if to.Sub(from) >= maxDays*24*time.Hour {
+		return nil, fmt.Errorf("the range is longer than %d days", maxDays)
+	}
+
+	days := make([]time.Time, 0)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days, nil
+}
@@ -59,3 +59,58 @@ func TestGetOrdinalSuffix(t *testing.T) {
 	date = time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC)
 	assert.Equal(t, "22nd", GetOrdinalSuffix(date.Day()))
 }
+
+func TestGetStartOfWeek(t *testing.T) {
+	// Wednesday
+	date := time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 7, 7, 0, 0, 0, 0, time.UTC), GetStartOfWeek(date, time.Sunday))
+	assert.Equal(t, time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC), GetStartOfWeek(date, time.Monday))
+	assert.Equal(t, date, GetStartOfWeek(date, time.Wednesday))
+}
+
+func TestDayOf(t *testing.T) {
+	istanbul := time.FixedZone("UTC+3", 3*60*60)
+	// 00:30 in UTC+3 is still the previous day in UTC
+	instant := time.Date(2024, 7, 8, 21, 30, 0, 0, time.UTC)
+
+	t.Run("Given time zone should use the day in the time zone", func(t *testing.T) {
+		clock := DayClock{Location: istanbul}
+		assert.Equal(t, time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC), clock.DayOf(instant))
+	})
+
+	t.Run("Given day start hour should count early hours as the previous day", func(t *testing.T) {
+		clock := DayClock{Location: istanbul, StartHour: 4}
+		assert.Equal(t, time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC), clock.DayOf(instant))
+		assert.Equal(t, time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC), clock.DayOf(instant.Add(4*time.Hour)))
+	})
+}
+
+func TestToDate(t *testing.T) {
+	local := time.Date(2024, 7, 9, 0, 30, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+
+	assert.Equal(t, time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC), ToDate(local))
+}
+
+func TestParseDays(t *testing.T) {
+	t.Run("Given a day should give the day", func(t *testing.T) {
+		days, err := ParseDays("2024-07-01")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{CreateDate(2024, 7, 1)}, days)
+	})
+
+	t.Run("Given a range should give every day of it", func(t *testing.T) {
+		days, err := ParseDays(" 2024-02-28  2024-03-01 ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{CreateDate(2024, 2, 28), CreateDate(2024, 2, 29), CreateDate(2024, 3, 1)}, days)
+	})
+
+	t.Run("Given an invalid range should fail", func(t *testing.T) {
+		for _, s := range []string{"", "2024-07-01 2024-07-02 2024-07-03", "2024-7-1", "2024-07-02 2024-07-01", "2024-01-01 2025-01-01"} {
+			_, err := ParseDays(s)
+			assert.Error(t, err, s)
+		}
+	})
+}